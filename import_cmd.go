@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"shortly-be/internal/cache"
+	"shortly-be/internal/config"
+	"shortly-be/internal/database"
+	"shortly-be/internal/importer"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+	"shortly-be/internal/service"
+)
+
+// runImportCommand implements "shortly-be import": it imports another shortener's export for a user
+// like POST /api/v1/import, printing every conflict and a summary
+func runImportCommand(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	email := flags.String("user", "", "email of the account that will own the links (required)")
+	format := flags.String("format", "", "export format: bitly, tinyurl, csv, yourls_json or yourls_sql (required)")
+	path := flags.String("file", "", "path of the export file (required)")
+	domain := flags.String("domain", "", "verified custom domain of the user to import into (default BASE_URL)")
+	importClicks := flags.Bool("import-clicks", false, "carry over historic click totals")
+	onConflict := flags.String("on-conflict", models.ImportOnConflictSkip, "what to do with links whose short code can't be kept: skip or generate")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: shortly-be import -user EMAIL -format FORMAT -file PATH [options]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *email == "" || *format == "" || *path == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *onConflict != models.ImportOnConflictSkip && *onConflict != models.ImportOnConflictGenerate {
+		log.Fatalf("Invalid -on-conflict '%s', expected skip or generate", *onConflict)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("Failed to open export file: %v", err)
+	}
+	records, err := importer.Parse(*format, file)
+	file.Close()
+	if err != nil {
+		log.Fatalf("Failed to read export file: %v", err)
+	}
+	if len(records) == 0 {
+		log.Fatalf("Export file contains no links")
+	}
+
+	cfg := config.Load()
+
+	db, err := database.NewConnection(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := database.RunMigrations(db); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	// Redis is optional here too, but keeps the server's short code cache in sync when available
+	var cacheClient cache.Cache
+	cacheClient, err = cache.NewRedisCache(cfg.RedisURL)
+	if err != nil {
+		log.Printf("Warning: Failed to connect to Redis (%v). Continuing without cache.", err)
+		cacheClient = nil
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	user, err := userRepo.FindByEmail(*email)
+	if err != nil {
+		log.Fatalf("Failed to find user '%s': %v", *email, err)
+	}
+
+	// Route paths are only known to the server; the seeded reserved words cover the current ones
+	codeFilter, err := newCodeFilter(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load short code blocklist: %v", err)
+	}
+	if err := service.NewReservedWordService(repository.NewReservedWordRepository(db), codeFilter).Reload(); err != nil {
+		log.Fatalf("Failed to load reserved words: %v", err)
+	}
+
+	urlRepo := repository.NewURLRepository(db)
+	codeGenerators, err := newCodeGenerators(cfg, urlRepo, codeFilter)
+	if err != nil {
+		log.Fatalf("Invalid short code configuration: %v", err)
+	}
+
+	urlService := service.NewURLService(
+		urlRepo,
+		userRepo,
+		repository.NewRoutingRuleRepository(db),
+		repository.NewDestinationRepository(db),
+		repository.NewRevisionRepository(db),
+		repository.NewDomainRepository(db),
+		repository.NewTagRepository(db),
+		repository.NewFolderRepository(db),
+		repository.NewUTMPresetRepository(db),
+		cfg.TrackingParams,
+		codeGenerators,
+		codeFilter,
+		nil, // Imports don't route visits
+		cacheClient,
+	)
+
+	opts := &models.ImportOptions{
+		Format:       *format,
+		ImportClicks: *importClicks,
+		OnConflict:   *onConflict,
+	}
+	if *domain != "" {
+		opts.Domain = domain
+	}
+
+	response := &models.ImportResponse{}
+	for start := 0; start < len(records); start += service.BulkBatchSize {
+		end := start + service.BulkBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		results, err := urlService.ImportURLs(records[start:end], user.ID, opts, cfg.BaseURL)
+		if err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+		response.Add(results)
+
+		for _, result := range results {
+			if result.Status != models.ImportStatusCreated {
+				fmt.Printf("row %d (%s): %s: %s\n", result.Row, result.OriginalCode, result.Status, result.Reason)
+			}
+		}
+		log.Printf("Processed %d/%d links", end, len(records))
+	}
+
+	fmt.Printf("Imported %d links: %d created, %d renamed, %d skipped, %d failed\n",
+		response.Created+response.Renamed, response.Created, response.Renamed, response.Skipped, response.Failed)
+}
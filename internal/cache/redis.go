@@ -14,6 +14,7 @@ type Cache interface {
 	Set(ctx context.Context, key string, value string, expiration time.Duration) error
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) (bool, error)
+	SetNX(ctx context.Context, key string, value string, expiration time.Duration) (bool, error)
 	SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	GetJSON(ctx context.Context, key string, dest interface{}) error
 }
@@ -78,6 +79,11 @@ func (r *redisCache) Exists(ctx context.Context, key string) (bool, error) {
 	return count > 0, nil
 }
 
+// SetNX stores a value only if the key doesn't exist yet and reports whether it did
+func (r *redisCache) SetNX(ctx context.Context, key string, value string, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, expiration).Result()
+}
+
 // SetJSON stores a JSON-serializable value in cache
 func (r *redisCache) SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	data, err := json.Marshal(value)
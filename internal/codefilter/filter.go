@@ -0,0 +1,187 @@
+// Package codefilter decides which short codes may be handed out: reserved words, which would shadow
+// the service's own routes or are held back by admins, and words of an offensive-language blocklist
+package codefilter
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	// ErrReserved is returned for codes that are reserved words
+	ErrReserved = errors.New("short code is reserved")
+	// ErrBlocked is returned for codes that contain a blocklisted word
+	ErrBlocked = errors.New("short code contains a blocked word")
+)
+
+//go:embed blocklist.txt
+var defaultBlocklist string
+
+// blockedWord is a normalized blocklist entry
+type blockedWord struct {
+	word  string
+	exact bool // Only the whole code matches, for short words that are part of harmless ones ("ass" in "classic")
+}
+
+// Filter checks codes against the reserved words and the blocklist. It is safe for concurrent use.
+type Filter struct {
+	mu       sync.RWMutex
+	routes   map[string]bool // First segments of the top-level routes, always reserved
+	reserved map[string]bool // Words reserved by admins
+	blocked  []blockedWord
+	unicode  bool // Custom codes may use Unicode letters and emoji
+}
+
+// New creates a filter with a blocklist as read by LoadBlocklist; allowUnicode lets custom codes
+// use Unicode letters and emoji (see CheckCharacters)
+func New(blocklist []string, allowUnicode bool) *Filter {
+	f := &Filter{
+		routes:   map[string]bool{},
+		reserved: map[string]bool{},
+		unicode:  allowUnicode,
+	}
+	for _, entry := range blocklist {
+		exact := strings.HasPrefix(entry, "=")
+		// Entries are spelled with letters; a stray "1" reads as an i
+		if word := normalize(strings.TrimPrefix(entry, "="), 'i'); word != "" {
+			f.blocked = append(f.blocked, blockedWord{word: word, exact: exact})
+		}
+	}
+	return f
+}
+
+// LoadBlocklist reads a blocklist file: one word per line, "#" starts a comment and a leading "=" matches
+// the word only as the whole code instead of anywhere in it. An empty path returns the built-in list.
+func LoadBlocklist(path string) ([]string, error) {
+	content := defaultBlocklist
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blocklist %s: %w", path, err)
+		}
+		content = string(data)
+	}
+
+	var words []string
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			words = append(words, line)
+		}
+	}
+	return words, nil
+}
+
+// ReserveRoutes reserves the first segment of route paths such as "/health" or "/api/v1/urls".
+// Parameter segments ("/:shortCode") are skipped.
+func (f *Filter) ReserveRoutes(paths []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, path := range paths {
+		segment := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+		if segment == "" || strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			continue
+		}
+		f.routes[strings.ToLower(segment)] = true
+	}
+}
+
+// Routes returns the reserved route segments in alphabetical order
+func (f *Filter) Routes() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	routes := make([]string, 0, len(f.routes))
+	for route := range f.routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// SetReserved replaces the words reserved by admins
+func (f *Filter) SetReserved(words []string) {
+	reserved := make(map[string]bool, len(words))
+	for _, word := range words {
+		reserved[strings.ToLower(word)] = true
+	}
+	f.mu.Lock()
+	f.reserved = reserved
+	f.mu.Unlock()
+}
+
+// Reserve adds a word reserved by an admin
+func (f *Filter) Reserve(word string) {
+	f.mu.Lock()
+	f.reserved[strings.ToLower(word)] = true
+	f.mu.Unlock()
+}
+
+// Release removes a word reserved by an admin; route segments stay reserved
+func (f *Filter) Release(word string) {
+	f.mu.Lock()
+	delete(f.reserved, strings.ToLower(word))
+	f.mu.Unlock()
+}
+
+// Check returns ErrReserved or ErrBlocked when the code can't be used, nil otherwise.
+// Reserved words match case-insensitively; blocklisted words also match through leetspeak
+// ("sh1t", "5h!t") and separators ("s-h-i-t").
+func (f *Filter) Check(code string) error {
+	lower := strings.ToLower(code)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.routes[lower] || f.reserved[lower] {
+		return ErrReserved
+	}
+
+	// A "1" may stand for an i or an l
+	variants := []string{normalize(code, 'i')}
+	if strings.Contains(code, "1") {
+		variants = append(variants, normalize(code, 'l'))
+	}
+	for _, variant := range variants {
+		for _, blocked := range f.blocked {
+			if blocked.exact && variant == blocked.word || !blocked.exact && strings.Contains(variant, blocked.word) {
+				return ErrBlocked
+			}
+		}
+	}
+	return nil
+}
+
+// Accept reports whether a code passes Check; generators use it to skip unusable codes
+func (f *Filter) Accept(code string) bool {
+	return f.Check(code) == nil
+}
+
+// leetspeak maps the digits and symbols commonly used in place of letters, except "1" (see normalize)
+var leetspeak = map[rune]rune{
+	'0': 'o', '3': 'e', '4': 'a', '5': 's', '6': 'g', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'l', '+': 't',
+}
+
+// normalize lowercases s, replaces leetspeak characters ("1" with one) and drops everything but letters
+func normalize(s string, one rune) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r == '1' {
+			r = one
+		} else if letter, ok := leetspeak[r]; ok {
+			r = letter
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
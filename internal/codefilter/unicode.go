@@ -0,0 +1,168 @@
+package codefilter
+
+import (
+	"errors"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// ErrInvalidCharacter is returned for custom codes with characters outside the allowed set
+	ErrInvalidCharacter = errors.New("short code can only contain letters, numbers, hyphens, and underscores")
+	// ErrInvalidUnicode is returned for Unicode codes with characters other than letters, marks, digits, "-", "_" and emoji
+	ErrInvalidUnicode = errors.New("short code can only contain letters, numbers, emoji, hyphens, and underscores")
+	// ErrMixedScripts is returned for codes that mix letters of unrelated scripts, such as Latin and Cyrillic
+	ErrMixedScripts = errors.New("short code mixes letters of different scripts")
+	// ErrConfusable is returned for non-ASCII codes that can be mistaken for an ASCII code (Cyrillic "аpple")
+	ErrConfusable = errors.New("short code can be mistaken for a code with plain letters")
+)
+
+// Normalize returns the NFC form of a code, in which codes are stored and looked up, so the composed and
+// decomposed spellings of "café" are the same code
+func Normalize(code string) string {
+	return norm.NFC.String(code)
+}
+
+// CheckCharacters validates the characters of a custom code in NFC form. Codes consist of ASCII letters,
+// digits, "-" and "_"; with Unicode codes enabled they may also use the letters and marks of one script
+// (Han may be combined with kana, Hangul or Bopomofo, and Latin with those) and emoji. Unicode codes that read
+// like a plain code are rejected here; those that read like another Unicode code are rejected when stored.
+func (f *Filter) CheckCharacters(code string) error {
+	if IsPlain(code) {
+		return nil
+	}
+	if !f.unicode {
+		return ErrInvalidCharacter
+	}
+
+	scripts := map[string]bool{}
+	for _, r := range code {
+		switch {
+		case r < unicode.MaxASCII && isASCIICodeRune(r):
+			if unicode.IsLetter(r) {
+				scripts["Latin"] = true
+			}
+		case unicode.IsLetter(r):
+			script := scriptOf(r)
+			if script == "" {
+				return ErrInvalidUnicode
+			}
+			scripts[script] = true
+		case unicode.IsMark(r) || isEmoji(r):
+		default:
+			return ErrInvalidUnicode
+		}
+	}
+	if !allowedScripts(scripts) {
+		return ErrMixedScripts
+	}
+	if IsPlain(Skeleton(code)) {
+		return ErrConfusable
+	}
+	return nil
+}
+
+// isASCIICodeRune reports whether r is allowed in plain codes
+func isASCIICodeRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_'
+}
+
+// IsPlain reports whether code only has characters allowed in plain codes: ASCII letters, digits, "-" and "_"
+func IsPlain(code string) bool {
+	for _, r := range code {
+		if !isASCIICodeRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmoji reports whether r is an emoji or part of an emoji sequence (joiner, variation selector,
+// skin tone modifier, flag tag)
+func isEmoji(r rune) bool {
+	switch {
+	case r == 0x200D, r == 0xFE0F, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F:
+		return true
+	case r >= 0x2300 && r <= 0x23FF, r >= 0x2600 && r <= 0x27BF, r >= 0x2B00 && r <= 0x2BFF, r >= 0x1F000 && r <= 0x1FAFF:
+		// Emoji blocks; other symbols such as box drawing could pass for "-" or "_"
+		return unicode.Is(unicode.So, r)
+	}
+	return false
+}
+
+// codeScripts are the scripts letters of Unicode codes may come from
+var codeScripts = func() map[string]*unicode.RangeTable {
+	scripts := make(map[string]*unicode.RangeTable, len(unicode.Scripts))
+	for name, table := range unicode.Scripts {
+		if name != "Common" && name != "Inherited" {
+			scripts[name] = table
+		}
+	}
+	return scripts
+}()
+
+// scriptOf returns the script of a letter, "" when it has none
+func scriptOf(r rune) string {
+	for name, table := range codeScripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// scriptCombinations are the sets of scripts that are written together (UTS #39 "highly restrictive")
+var scriptCombinations = []map[string]bool{
+	{"Latin": true, "Han": true, "Hiragana": true, "Katakana": true},
+	{"Latin": true, "Han": true, "Bopomofo": true},
+	{"Latin": true, "Han": true, "Hangul": true},
+}
+
+// allowedScripts reports whether the letters of one code may come from scripts
+func allowedScripts(scripts map[string]bool) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+	for _, combination := range scriptCombinations {
+		allowed := true
+		for script := range scripts {
+			if !combination[script] {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// confusables maps letters to the ASCII letter they are easily mistaken for
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p', 'с': 'c', 'т': 't',
+	'у': 'y', 'х': 'x', 'і': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'һ': 'h', 'ӏ': 'l',
+	'ү': 'y',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'ζ': 'z', 'η': 'n', 'ι': 'i', 'κ': 'k', 'μ': 'u', 'ν': 'v', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'γ': 'y',
+	// Armenian
+	'օ': 'o', 'ս': 'u', 'հ': 'h', 'ո': 'n', 'զ': 'q', 'ց': 'g',
+	// Latin letters outside ASCII
+	'ı': 'i', 'ɑ': 'a', 'ɩ': 'i', 'ʟ': 'l', 'ɪ': 'i', 'ȷ': 'j', 'ƅ': 'b', 'ɡ': 'g',
+}
+
+// Skeleton returns the code as it could be read: compatibility characters (fullwidth letters, ligatures)
+// are folded and confusable letters replaced by their ASCII lookalike. Codes with the same skeleton can't
+// be told apart, so only one of them may exist on a domain.
+func Skeleton(code string) string {
+	runes := []rune(norm.NFKC.String(code))
+	for i, r := range runes {
+		if lookalike, ok := confusables[unicode.ToLower(r)]; ok {
+			runes[i] = lookalike
+		}
+	}
+	return string(runes)
+}
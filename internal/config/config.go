@@ -1,9 +1,12 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -13,16 +16,38 @@ type Config struct {
 	BaseURL               string // Backend base URL
 	FrontendURL           string // Frontend base URL (for QR codes and short URLs)
 	RedisURL              string
-	JWTSecret             string  // Secret key for JWT token signing
-	JWTTTL                int     // JWT token expiration time in hours
-	RateLimitRPS          float64 // Rate limit for general API endpoints (requests per second)
-	RateLimitBurst        int     // Burst size for rate limiting
-	RateLimitAuthRPS      float64 // Rate limit for auth endpoints (stricter)
-	RateLimitAuthBurst    int     // Burst size for auth endpoints
-	RateLimitShortenRPS   float64 // Rate limit for URL shortening (stricter)
-	RateLimitShortenBurst int     // Burst size for URL shortening
+	JWTSecret             string   // Secret key for JWT token signing
+	JWTTTL                int      // JWT token expiration time in hours
+	RateLimitRPS          float64  // Rate limit for general API endpoints (requests per second)
+	RateLimitBurst        int      // Burst size for rate limiting
+	RateLimitAuthRPS      float64  // Rate limit for auth endpoints (stricter)
+	RateLimitAuthBurst    int      // Burst size for auth endpoints
+	RateLimitShortenRPS   float64  // Rate limit for URL shortening (stricter)
+	RateLimitShortenBurst int      // Burst size for URL shortening
+	LinkUnlockTTL         int      // Minutes a visitor stays unlocked after entering a link password
+	GeoIPDBPath           string   // Path to a local GeoLite2-Country .mmdb file (optional, enables country routing)
+	AppleAppSiteAssocPath string   // Path to the apple-app-site-association JSON served under /.well-known (optional)
+	AssetLinksPath        string   // Path to the assetlinks.json served under /.well-known (optional)
+	TrashRetentionDays    int      // Days a deleted link stays restorable before it is purged
+	TrashPurgeInterval    int      // Minutes between runs of the trash purge job
+	TrackingParams        []string // Query parameters stripped from new links unless a user overrides the list ("utm_*" matches a prefix)
+	ShortCodeStrategy     string   // Default generation strategy: random, sequence, hashids or words
+	ShortCodeLength       int      // Minimum length of generated codes (random, sequence and hashids)
+	ShortCodeAlphabet     string   // Characters of random codes, empty for the built-in unambiguous alphabet
+	ShortCodeSalt         string   // Secret salt of hashids codes
+	ShortCodePoolSize     int      // Pre-generated codes kept in the pool, 0 disables the pool
+	ShortCodePoolLease    int      // Pooled codes each instance leases into memory at a time
+	ShortCodePoolInterval int      // Seconds between pool refills
+	MetricsAddr           string   // Address of the internal listener serving /metrics, empty disables metrics
+	BlocklistPath         string   // File of words short codes must not contain, empty for the built-in list
+	ReservedWordsReload   int      // Seconds between reloads of the reserved words
+	UnicodeShortCodes     bool     // Allow custom codes with Unicode letters and emoji
 }
 
+// defaultTrackingParams are the click identifiers of common ad and email platforms. UTM parameters are kept,
+// since links are often built to carry a campaign; users who want them stripped add "utm_*" to their own list.
+var defaultTrackingParams = []string{"fbclid", "gclid", "dclid", "gbraid", "wbraid", "msclkid", "yclid", "twclid", "igshid", "mc_cid", "mc_eid", "_hsenc", "_hsmi"}
+
 func Load() *Config {
 	// Try to load .env file (ignore error if file doesn't exist)
 	if err := godotenv.Load(); err != nil {
@@ -43,9 +68,43 @@ func Load() *Config {
 		RateLimitAuthBurst:    getEnvInt("RATE_LIMIT_AUTH_BURST", 10),     // Allow bursts of 10
 		RateLimitShortenRPS:   getEnvFloat("RATE_LIMIT_SHORTEN_RPS", 2.0), // 2 requests per second for URL shortening (stricter)
 		RateLimitShortenBurst: getEnvInt("RATE_LIMIT_SHORTEN_BURST", 5),   // Allow bursts of 5
+		LinkUnlockTTL:         getEnvInt("LINK_UNLOCK_TTL_MINUTES", 60),   // Unlock cookie valid for 1 hour
+		GeoIPDBPath:           getEnv("GEOIP_DB_PATH", ""),
+		AppleAppSiteAssocPath: getEnv("APPLE_APP_SITE_ASSOCIATION_PATH", ""),
+		AssetLinksPath:        getEnv("ANDROID_ASSETLINKS_PATH", ""),
+		TrashRetentionDays:    getEnvInt("TRASH_RETENTION_DAYS", 30),         // Deleted links restorable for 30 days
+		TrashPurgeInterval:    getEnvInt("TRASH_PURGE_INTERVAL_MINUTES", 60), // Purge expired trash hourly
+		TrackingParams:        getEnvList("TRACKING_PARAMS", defaultTrackingParams),
+		ShortCodeStrategy:     getEnv("SHORT_CODE_STRATEGY", "random"),
+		ShortCodeLength:       getEnvInt("SHORT_CODE_LENGTH", 8),
+		ShortCodeAlphabet:     getEnv("SHORT_CODE_ALPHABET", ""),
+		ShortCodeSalt:         getEnv("SHORT_CODE_SALT", ""),
+		ShortCodePoolSize:     getEnvInt("SHORT_CODE_POOL_SIZE", 10000),
+		ShortCodePoolLease:    getEnvInt("SHORT_CODE_POOL_LEASE", 100),
+		ShortCodePoolInterval: getEnvInt("SHORT_CODE_POOL_REFILL_SECONDS", 60),
+		MetricsAddr:           getEnv("METRICS_ADDR", ""),
+		BlocklistPath:         getEnv("PROFANITY_BLOCKLIST_PATH", ""),
+		ReservedWordsReload:   getEnvInt("RESERVED_WORDS_RELOAD_SECONDS", 60),
+		UnicodeShortCodes:     getEnvBool("UNICODE_SHORT_CODES", false),
 	}
 }
 
+// LoadJSONFile reads an optional JSON document referenced by the configuration.
+// An empty path returns nil without error.
+func LoadJSONFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%s does not contain valid JSON", path)
+	}
+	return data, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -70,3 +129,28 @@ func getEnvFloat(key string, defaultValue float64) float64 {
 	}
 	return defaultValue
 }
+
+// getEnvBool reads a boolean such as "true", "1" or "false"
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList reads a comma-separated list; an unset variable yields the default
+func getEnvList(key string, defaultValue []string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
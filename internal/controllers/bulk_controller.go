@@ -0,0 +1,236 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"shortly-be/internal/models"
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxBulkUploadSize caps the request body of a bulk creation
+const maxBulkUploadSize = 10 << 20 // 10 MB
+
+// maxBulkRows caps the number of links in a single bulk creation
+const maxBulkRows = 10000
+
+// bulkSyncLimit is the largest upload processed within the request; larger ones run as a background job
+const bulkSyncLimit = 500
+
+// bulkCSVColumns are the columns accepted in a CSV upload; only url is required
+var bulkCSVColumns = map[string]bool{"url": true, "short_code": true, "expires_at": true, "tags": true}
+
+type BulkController struct {
+	urlService service.URLService
+	jobService service.JobService
+	baseURL    string
+}
+
+func NewBulkController(urlService service.URLService, jobService service.JobService, baseURL string) *BulkController {
+	return &BulkController{
+		urlService: urlService,
+		jobService: jobService,
+		baseURL:    baseURL,
+	}
+}
+
+// CreateBulk handles POST /api/v1/shorten/bulk - creates many links from a JSON array of
+// shorten requests, a text/csv body or a multipart "file" upload (columns: url, short_code, expires_at, tags).
+// Uploads of up to bulkSyncLimit rows return per-row results; larger ones return 202 with a job to poll.
+func (bc *BulkController) CreateBulk(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBulkUploadSize)
+	rows, err := readBulkRows(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid upload",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Upload contains no links",
+		})
+		return
+	}
+	if len(rows) > maxBulkRows {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Upload contains %d links, the limit is %d", len(rows), maxBulkRows),
+		})
+		return
+	}
+
+	if len(rows) > bulkSyncLimit {
+		job, err := bc.jobService.StartBulkCreate(userID, rows, bc.baseURL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusAccepted, job)
+		return
+	}
+
+	response := &models.BulkCreateResponse{Results: make([]models.BulkCreateResult, 0, len(rows))}
+	response.Add(bc.urlService.CreateShortURLs(rows, &userID, bc.baseURL))
+	c.JSON(http.StatusOK, response)
+}
+
+// readBulkRows parses the rows of a bulk upload according to its content type
+func readBulkRows(c *gin.Context) ([]models.BulkCreateRow, error) {
+	switch c.ContentType() {
+	case "multipart/form-data":
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("missing CSV file field 'file': %w", err)
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return parseBulkCSV(file)
+	case "text/csv":
+		return parseBulkCSV(c.Request.Body)
+	default:
+		return parseBulkJSON(c.Request.Body)
+	}
+}
+
+// parseBulkJSON reads a JSON array of shorten requests. A malformed element only fails its own row.
+func parseBulkJSON(r io.Reader) ([]models.BulkCreateRow, error) {
+	var elements []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&elements); err != nil {
+		return nil, fmt.Errorf("body must be a JSON array of links: %w", err)
+	}
+
+	rows := make([]models.BulkCreateRow, len(elements))
+	for i, element := range elements {
+		rows[i].Row = i + 1
+		var req models.CreateURLRequest
+		if err := json.Unmarshal(element, &req); err != nil {
+			rows[i].Error = err.Error()
+			continue
+		}
+		rows[i].Request = &req
+		validateBulkRow(&rows[i])
+	}
+	return rows, nil
+}
+
+// parseBulkCSV reads a CSV upload with a header row. Tags are separated by semicolons and
+// expires_at is an RFC 3339 timestamp. A row with invalid values only fails itself.
+func parseBulkCSV(r io.Reader) ([]models.BulkCreateRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Rows with a wrong field count fail individually
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // Spreadsheet exports may start with a byte order mark
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !bulkCSVColumns[name] {
+			return nil, fmt.Errorf("unknown CSV column '%s'", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["url"]; !ok {
+		return nil, fmt.Errorf("CSV header must include a 'url' column")
+	}
+
+	var rows []models.BulkCreateRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		row := models.BulkCreateRow{Row: len(rows) + 1}
+		if len(record) != len(header) {
+			row.Error = fmt.Sprintf("expected %d fields, got %d", len(header), len(record))
+			rows = append(rows, row)
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		req := &models.CreateURLRequest{URL: field("url")}
+		if shortCode := field("short_code"); shortCode != "" {
+			req.ShortCode = &shortCode
+		}
+		if expiresAt := field("expires_at"); expiresAt != "" {
+			parsed, err := time.Parse(time.RFC3339, expiresAt)
+			if err != nil {
+				row.Error = "expires_at must be an RFC 3339 timestamp"
+				rows = append(rows, row)
+				continue
+			}
+			req.ExpiresAt = &parsed
+		}
+		if tags := field("tags"); tags != "" {
+			req.Tags = strings.Split(tags, ";")
+		}
+
+		row.Request = req
+		validateBulkRow(&row)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// validateBulkRow applies the binding rules of POST /api/v1/shorten to a row and normalizes its times to UTC
+func validateBulkRow(row *models.BulkCreateRow) {
+	if err := binding.Validator.ValidateStruct(row.Request); err != nil {
+		row.Error = err.Error()
+		return
+	}
+
+	// Ensure startsAt and expiresAt are in UTC
+	if row.Request.StartsAt != nil {
+		utcTime := row.Request.StartsAt.UTC()
+		row.Request.StartsAt = &utcTime
+	}
+	if row.Request.ExpiresAt != nil {
+		utcTime := row.Request.ExpiresAt.UTC()
+		row.Request.ExpiresAt = &utcTime
+	}
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DomainController struct {
+	domainService service.DomainService
+}
+
+func NewDomainController(domainService service.DomainService) *DomainController {
+	return &DomainController{
+		domainService: domainService,
+	}
+}
+
+// ListDomains handles GET /api/v1/domains - returns the custom domains of the authenticated user
+func (dc *DomainController) ListDomains(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	domains, err := dc.domainService.ListDomains(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, domains)
+}
+
+// AddDomain handles POST /api/v1/domains - registers a custom domain pending DNS verification
+func (dc *DomainController) AddDomain(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.AddDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	domain, err := dc.domainService.AddDomain(userID, req.Hostname)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain)
+}
+
+// VerifyDomain handles POST /api/v1/domains/:hostname/verify - checks the domain's DNS TXT record
+func (dc *DomainController) VerifyDomain(c *gin.Context) {
+	hostname := c.Param("hostname")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	domain, err := dc.domainService.VerifyDomain(userID, hostname)
+	if err != nil {
+		c.JSON(domainErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain)
+}
+
+// DeleteDomain handles DELETE /api/v1/domains/:hostname - removes a custom domain without links
+func (dc *DomainController) DeleteDomain(c *gin.Context) {
+	hostname := c.Param("hostname")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	if err := dc.domainService.DeleteDomain(userID, hostname); err != nil {
+		c.JSON(domainErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Domain deleted successfully",
+	})
+}
+
+// domainErrorStatus maps domain errors to HTTP status codes
+func domainErrorStatus(err error) int {
+	if errors.Is(err, repository.ErrDomainNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
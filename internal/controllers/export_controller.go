@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/export"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// urlExportColumns are the columns of link exports
+var urlExportColumns = []string{
+	"domain", "short_code", "short_url", "original_url", "title", "tags", "folder_id", "click_count",
+	"max_clicks", "redirect_type", "created_at", "starts_at", "expires_at", "archived_at",
+}
+
+// clickExportColumns are the columns of click exports
+var clickExportColumns = []string{"id", "short_code", "clicked_at", "variant_id", "variant_label"}
+
+type ExportController struct {
+	urlService service.URLService
+	baseURL    string
+}
+
+func NewExportController(urlService service.URLService, baseURL string) *ExportController {
+	return &ExportController{
+		urlService: urlService,
+		baseURL:    baseURL,
+	}
+}
+
+// ExportURLs handles GET /api/v1/urls/export - streams all links of the authenticated user (active and archived)
+// as CSV, JSON or NDJSON (?format=), accepting the filters of GET /api/v1/urls
+func (ec *ExportController) ExportURLs(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var query models.URLListQuery
+	var exportQuery models.ExportQuery
+	if err := bindExportQuery(c, &query, &exportQuery); err != nil {
+		return
+	}
+
+	streamExport(c, exportQuery.Format, "links", urlExportColumns, func(write func(values ...interface{}) error) error {
+		return ec.urlService.ExportURLs(userID, &query, func(url *entities.URL) error {
+			tagNames := make([]string, len(url.Tags))
+			for i, tag := range url.Tags {
+				tagNames[i] = tag.Name
+			}
+			return write(
+				url.Domain, url.ShortCode, service.ShortURL(ec.baseURL, url.Domain, url.ShortCode), url.OriginalURL,
+				url.Title, tagNames, url.FolderID, url.ClickCount,
+				url.MaxClicks, url.RedirectType, url.CreatedAt, url.StartsAt, url.ExpiresAt, url.ArchivedAt,
+			)
+		})
+	})
+}
+
+// ExportClicks handles GET /api/v1/url/:shortCode/analytics/export - streams the individual clicks of a link
+// as CSV, JSON or NDJSON (?format=), optionally limited to ?from= and ?to=
+func (ec *ExportController) ExportClicks(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var exportQuery models.ExportQuery
+	if err := bindExportQuery(c, nil, &exportQuery); err != nil {
+		return
+	}
+
+	filename := fmt.Sprintf("clicks-%s", shortCode)
+	streamExport(c, exportQuery.Format, filename, clickExportColumns, func(write func(values ...interface{}) error) error {
+		return ec.urlService.ExportClicks(linkDomain(c), shortCode, &userID, exportQuery.From, exportQuery.To, func(click *entities.URLClick) error {
+			return write(click.ID, shortCode, click.ClickedAt, click.VariantID, click.VariantLabel)
+		})
+	})
+}
+
+// bindExportQuery binds the listing filters (when query is non-nil) and export options, responding 400 on errors
+func bindExportQuery(c *gin.Context, query *models.URLListQuery, exportQuery *models.ExportQuery) error {
+	err := c.ShouldBindQuery(exportQuery)
+	if err == nil && query != nil {
+		err = c.ShouldBindQuery(query)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return err
+	}
+	if exportQuery.Format == "" {
+		exportQuery.Format = export.FormatCSV
+	}
+	return nil
+}
+
+// streamExport runs an export, writing each record as it is produced. Headers are only sent with the first
+// record, so errors raised before any output (e.g. an unknown link) still get a JSON error response;
+// an error mid-stream can only cut the download short.
+func streamExport(c *gin.Context, format, filename string, columns []string, run func(write func(values ...interface{}) error) error) {
+	var writer export.Writer
+	start := func() error {
+		c.Header("Content-Type", export.ContentType(format))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-%s.%s", filename, time.Now().UTC().Format("20060102"), format))
+		c.Status(http.StatusOK)
+		var err error
+		writer, err = export.NewWriter(format, c.Writer, columns)
+		return err
+	}
+
+	err := run(func(values ...interface{}) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return writer.Write(values...)
+	})
+
+	if err != nil && writer == nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrFolderNotFound) || strings.HasPrefix(err.Error(), "URL not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Export aborted: %v", err)
+		c.Abort()
+		return
+	}
+
+	if writer == nil {
+		if err := start(); err != nil {
+			log.Printf("ERROR: Export failed: %v", err)
+			return
+		}
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("ERROR: Export aborted: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FolderController struct {
+	folderService service.FolderService
+}
+
+func NewFolderController(folderService service.FolderService) *FolderController {
+	return &FolderController{
+		folderService: folderService,
+	}
+}
+
+// ListFolders handles GET /api/v1/folders - returns the folders of the authenticated user
+func (fc *FolderController) ListFolders(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	folders, err := fc.folderService.ListFolders(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, folders)
+}
+
+// CreateFolder handles POST /api/v1/folders - creates a folder (nested under parent_id when given)
+func (fc *FolderController) CreateFolder(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.FolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	folder, err := fc.folderService.CreateFolder(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, folder)
+}
+
+// UpdateFolder handles PATCH /api/v1/folders/:folderId - renames or moves a folder
+func (fc *FolderController) UpdateFolder(c *gin.Context) {
+	folderID := c.Param("folderId")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.UpdateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	folder, err := fc.folderService.UpdateFolder(userID, folderID, &req)
+	if err != nil {
+		c.JSON(folderErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, folder)
+}
+
+// DeleteFolder handles DELETE /api/v1/folders/:folderId - removes a folder and its subfolders, unfiling their links
+func (fc *FolderController) DeleteFolder(c *gin.Context) {
+	folderID := c.Param("folderId")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	if err := fc.folderService.DeleteFolder(userID, folderID); err != nil {
+		c.JSON(folderErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Folder deleted successfully",
+	})
+}
+
+// folderErrorStatus maps folder errors to HTTP status codes
+func folderErrorStatus(err error) int {
+	if errors.Is(err, repository.ErrFolderNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"shortly-be/internal/importer"
+	"shortly-be/internal/models"
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportUploadSize caps the export file of an import; SQL dumps are larger than CSV exports
+const maxImportUploadSize = 50 << 20 // 50 MB
+
+// maxImportRows caps the number of links in a single import
+const maxImportRows = 100000
+
+type ImportController struct {
+	jobService service.JobService
+	baseURL    string
+}
+
+func NewImportController(jobService service.JobService, baseURL string) *ImportController {
+	return &ImportController{
+		jobService: jobService,
+		baseURL:    baseURL,
+	}
+}
+
+// ImportLinks handles POST /api/v1/import?format=bitly|tinyurl|csv|yourls_json|yourls_sql - imports the links of
+// another shortener's export, sent as a multipart "file" upload or as the raw body.
+// Always returns 202 with a job to poll; its result lists every link as created, renamed, skipped or failed.
+func (ic *ImportController) ImportLinks(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var opts models.ImportOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportUploadSize)
+	records, err := readImportRecords(c, opts.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid export file",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(records) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Export file contains no links",
+		})
+		return
+	}
+	if len(records) > maxImportRows {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Export file contains %d links, the limit is %d", len(records), maxImportRows),
+		})
+		return
+	}
+
+	job, err := ic.jobService.StartImport(userID, records, &opts, ic.baseURL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// readImportRecords parses the export file of an import from a multipart "file" field or the request body
+func readImportRecords(c *gin.Context, format string) ([]importer.Record, error) {
+	var r io.Reader = c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("missing file field 'file': %w", err)
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+	return importer.Parse(format, r)
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"shortly-be/internal/repository"
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobController struct {
+	jobService service.JobService
+}
+
+func NewJobController(jobService service.JobService) *JobController {
+	return &JobController{
+		jobService: jobService,
+	}
+}
+
+// GetJob handles GET /api/v1/jobs/:jobId - returns the progress of a background job and its result once finished
+func (jc *JobController) GetJob(c *gin.Context) {
+	jobID := c.Param("jobId")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	job, err := jc.jobService.GetJob(userID, jobID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrJobNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"shortly-be/internal/shortcode"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetricsController struct {
+	codePool *shortcode.Pool
+}
+
+// NewMetricsController exposes operational metrics in the Prometheus text format.
+// A nil pool leaves out the short code pool metrics.
+func NewMetricsController(codePool *shortcode.Pool) *MetricsController {
+	return &MetricsController{
+		codePool: codePool,
+	}
+}
+
+// Metrics handles GET /metrics
+func (mc *MetricsController) Metrics(c *gin.Context) {
+	var b strings.Builder
+	if mc.codePool != nil {
+		stats := mc.codePool.Stats()
+		writeMetric(&b, "shortly_code_pool_size", "gauge", "Target number of codes in the short code pool", int64(stats.Size))
+		writeMetric(&b, "shortly_code_pool_depth", "gauge", "Unused codes in the short code pool at the last count", stats.Depth)
+		writeMetric(&b, "shortly_code_pool_leased", "gauge", "Pooled codes this instance holds in memory", int64(stats.Leased))
+		writeMetric(&b, "shortly_code_pool_served_total", "counter", "Pooled codes handed to new links", stats.Served)
+		writeMetric(&b, "shortly_code_pool_misses_total", "counter", "New links that found the pool empty and generated a code", stats.Misses)
+		writeMetric(&b, "shortly_code_pool_generated_total", "counter", "Codes this instance added to the pool", stats.Generated)
+	}
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
+
+func writeMetric(b *strings.Builder, name, kind, help string, value int64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, kind, name, value)
+}
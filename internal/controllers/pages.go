@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// pageTemplates holds the HTML pages served to visitors of short links
+var pageTemplates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// renderPage renders one of the embedded HTML templates
+func renderPage(c *gin.Context, status int, name string, data interface{}) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	c.Status(status)
+	if err := pageTemplates.ExecuteTemplate(c.Writer, name, data); err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}
+}
@@ -3,6 +3,8 @@ package controllers
 import (
 	"net/http"
 
+	"shortly-be/internal/service"
+
 	"github.com/gin-gonic/gin"
 	"github.com/skip2/go-qrcode"
 )
@@ -19,7 +21,7 @@ func NewQRCodeController(baseURL string) *QRCodeController {
 
 // GenerateQRCode handles GET /api/v1/qrcode/:shortCode - generates QR code for a short URL
 func (qc *QRCodeController) GenerateQRCode(c *gin.Context) {
-	shortCode := c.Param("shortCode")
+	shortCode := shortCodeParam(c)
 	if shortCode == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Short code is required",
@@ -27,8 +29,8 @@ func (qc *QRCodeController) GenerateQRCode(c *gin.Context) {
 		return
 	}
 
-	// Construct the full short URL
-	shortURL := qc.baseURL + "/" + shortCode
+	// Construct the full short URL (on the link's custom domain when ?domain= is given)
+	shortURL := service.ShortURL(qc.baseURL, linkDomain(c), shortCode)
 
 	// Generate QR code (256x256 pixels, medium error recovery)
 	qrCode, err := qrcode.New(shortURL, qrcode.Medium)
@@ -53,4 +55,3 @@ func (qc *QRCodeController) GenerateQRCode(c *gin.Context) {
 	c.Header("Content-Disposition", "inline; filename=qrcode.png")
 	c.Data(http.StatusOK, "image/png", pngData)
 }
-
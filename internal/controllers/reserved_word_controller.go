@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReservedWordController struct {
+	wordService service.ReservedWordService
+}
+
+func NewReservedWordController(wordService service.ReservedWordService) *ReservedWordController {
+	return &ReservedWordController{
+		wordService: wordService,
+	}
+}
+
+// ListWords handles GET /api/v1/admin/reserved-words - returns the reserved words and route paths
+func (wc *ReservedWordController) ListWords(c *gin.Context) {
+	response, err := wc.wordService.ListWords()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReserveWord handles POST /api/v1/admin/reserved-words - reserves a word so it can't be used as a short code
+func (wc *ReservedWordController) ReserveWord(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.ReservedWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	word, err := wc.wordService.ReserveWord(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, word)
+}
+
+// ReleaseWord handles DELETE /api/v1/admin/reserved-words/:word - makes a reserved word usable again
+func (wc *ReservedWordController) ReleaseWord(c *gin.Context) {
+	if err := wc.wordService.ReleaseWord(c.Param("word")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrReservedWordNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reserved word released successfully",
+	})
+}
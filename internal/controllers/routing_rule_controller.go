@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"shortly-be/internal/models"
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoutingRuleController struct {
+	ruleService service.RoutingRuleService
+}
+
+func NewRoutingRuleController(ruleService service.RoutingRuleService) *RoutingRuleController {
+	return &RoutingRuleController{
+		ruleService: ruleService,
+	}
+}
+
+// ListRules handles GET /api/v1/url/:shortCode/rules - returns the routing rules in evaluation order
+func (rc *RoutingRuleController) ListRules(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	rules, err := rc.ruleService.ListRules(linkDomain(c), shortCode, &userID)
+	if err != nil {
+		c.JSON(ruleErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// CreateRule handles POST /api/v1/url/:shortCode/rules - adds a routing rule
+func (rc *RoutingRuleController) CreateRule(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.RoutingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := rc.ruleService.CreateRule(linkDomain(c), shortCode, &userID, &req)
+	if err != nil {
+		c.JSON(ruleErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule handles PUT /api/v1/url/:shortCode/rules/:ruleId - replaces a routing rule
+func (rc *RoutingRuleController) UpdateRule(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+	ruleID := c.Param("ruleId")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.RoutingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := rc.ruleService.UpdateRule(linkDomain(c), shortCode, &userID, ruleID, &req)
+	if err != nil {
+		c.JSON(ruleErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule handles DELETE /api/v1/url/:shortCode/rules/:ruleId - removes a routing rule
+func (rc *RoutingRuleController) DeleteRule(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+	ruleID := c.Param("ruleId")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	if err := rc.ruleService.DeleteRule(linkDomain(c), shortCode, &userID, ruleID); err != nil {
+		c.JSON(ruleErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Routing rule deleted successfully",
+	})
+}
+
+// ReorderRules handles PUT /api/v1/url/:shortCode/rules - sets the evaluation order of all rules
+func (rc *RoutingRuleController) ReorderRules(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.ReorderRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rules, err := rc.ruleService.ReorderRules(linkDomain(c), shortCode, &userID, req.RuleIDs)
+	if err != nil {
+		c.JSON(ruleErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// ruleErrorStatus maps routing rule errors to HTTP status codes
+func ruleErrorStatus(err error) int {
+	if strings.HasPrefix(err.Error(), "URL not found") || err.Error() == "routing rule not found" {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -1,11 +1,18 @@
 package controllers
 
 import (
+	"errors"
+	"html/template"
 	"net/http"
+	neturl "net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	"shortly-be/internal/codefilter"
+	"shortly-be/internal/jwt"
 	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
 	"shortly-be/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -13,13 +20,26 @@ import (
 
 type ShortenerController struct {
 	urlService service.URLService
+	jwtService *jwt.JWTService
 	baseURL    string
+	unlockTTL  time.Duration // Lifetime of the cookie issued after entering a link password
 }
 
-func NewShortenerController(urlService service.URLService, baseURL string) *ShortenerController {
+// unlockCookieName is the cookie holding the unlock token, scoped to the short link's path
+const unlockCookieName = "shortly_unlock"
+
+// variantCookieName is the cookie remembering the A/B destination served, scoped to the short link's path
+const variantCookieName = "shortly_variant"
+
+// variantCookieTTL is how long a visitor stays on the same A/B destination
+const variantCookieTTL = 30 * 24 * time.Hour
+
+func NewShortenerController(urlService service.URLService, jwtService *jwt.JWTService, baseURL string, unlockTTL time.Duration) *ShortenerController {
 	return &ShortenerController{
 		urlService: urlService,
+		jwtService: jwtService,
 		baseURL:    baseURL,
+		unlockTTL:  unlockTTL,
 	}
 }
 
@@ -45,7 +65,11 @@ func (sc *ShortenerController) CreateShortURL(c *gin.Context) {
 	}
 	userID := userIDStr.(string)
 
-	// Ensure expiresAt is in UTC
+	// Ensure startsAt and expiresAt are in UTC
+	if req.StartsAt != nil {
+		utcTime := req.StartsAt.UTC()
+		req.StartsAt = &utcTime
+	}
 	if req.ExpiresAt != nil {
 		utcTime := req.ExpiresAt.UTC()
 		req.ExpiresAt = &utcTime
@@ -53,20 +77,69 @@ func (sc *ShortenerController) CreateShortURL(c *gin.Context) {
 
 	response, err := sc.urlService.CreateShortURL(&req, &userID, sc.baseURL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrShortCodeTaken) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, response)
+	// An existing link returned because of reuse_existing was not created
+	status := http.StatusCreated
+	if response.Reused {
+		status = http.StatusOK
+	}
+	c.JSON(status, response)
 }
 
-// RedirectToURL handles GET /:shortCode - redirects to original URL
-func (sc *ShortenerController) RedirectToURL(c *gin.Context) {
+// shortCodeParam returns the :shortCode path parameter in the form codes are stored in. Gin has already
+// percent-decoded the path, but codes never contain "%", so any left over was encoded twice (e.g. by a proxy)
+// and is decoded again; the result is NFC-normalized like new codes.
+func shortCodeParam(c *gin.Context) string {
 	shortCode := c.Param("shortCode")
+	if strings.Contains(shortCode, "%") {
+		if decoded, err := neturl.PathUnescape(shortCode); err == nil {
+			shortCode = decoded
+		}
+	}
+	return codefilter.Normalize(shortCode)
+}
+
+// linkDomain returns the custom domain a management request targets (?domain=), empty for the default domain
+func linkDomain(c *gin.Context) string {
+	return strings.ToLower(strings.TrimSpace(c.Query("domain")))
+}
 
-	originalURL, err := sc.urlService.GetOriginalURL(shortCode)
+// RedirectToURL handles GET /:shortCode and GET /:shortCode/*rest - redirects to original URL
+func (sc *ShortenerController) RedirectToURL(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+
+	variantID, _ := c.Cookie(variantCookieName)
+	target, err := sc.urlService.GetOriginalURL(&models.RedirectRequest{
+		Host:           c.Request.Host,
+		ShortCode:      shortCode,
+		Unlocked:       sc.unlockChecker(c),
+		IP:             c.ClientIP(),
+		UserAgent:      c.GetHeader("User-Agent"),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+		VariantID:      variantID,
+		Path:           c.Param("rest"),
+		RawQuery:       c.Request.URL.RawQuery,
+	})
+	if errors.Is(err, service.ErrPasswordRequired) {
+		// Post back to the same URL so forwarded path and query survive the unlock
+		renderPage(c, http.StatusUnauthorized, "unlock.html", gin.H{
+			"Action": c.Request.URL.RequestURI(),
+		})
+		return
+	}
+	if errors.Is(err, repository.ErrURLNotYetActive) {
+		renderPage(c, http.StatusNotFound, "coming_soon.html", nil)
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Short URL not found or expired",
@@ -74,15 +147,153 @@ func (sc *ShortenerController) RedirectToURL(c *gin.Context) {
 		return
 	}
 
-	// Redirect with 301 (Moved Permanently)
-	c.Redirect(http.StatusMovedPermanently, originalURL)
+	// Remember the A/B destination so the visitor keeps seeing the same variant
+	if target.Sticky && target.VariantID != nil {
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(variantCookieName, *target.VariantID, int(variantCookieTTL.Seconds()), "/"+neturl.PathEscape(shortCode), "", strings.HasPrefix(sc.baseURL, "https://"), true)
+	}
+
+	// Mobile visitors of deep-linked URLs get an interstitial that tries the app before falling back
+	if target.AppURL != "" {
+		renderPage(c, http.StatusOK, "deeplink.html", gin.H{
+			// App URLs use custom schemes that html/template would otherwise replace; they are validated on save.
+			// The fallback is a web URL and stays subject to the template's URL filtering.
+			"AppURL":      template.URL(target.AppURL),
+			"FallbackURL": target.FallbackURL,
+		})
+		return
+	}
+
+	// Redirect with the link's configured status code (301, 302, 307 or 308)
+	c.Redirect(target.RedirectType, target.Destination)
+}
+
+// UnlockURL handles POST /:shortCode and POST /:shortCode/*rest - checks the submitted password and sets the unlock cookie
+func (sc *ShortenerController) UnlockURL(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+	linkPath := "/" + neturl.PathEscape(shortCode)
+	linkURI := c.Request.URL.RequestURI()
+
+	var req models.UnlockURLRequest
+	if err := c.ShouldBind(&req); err != nil {
+		renderPage(c, http.StatusBadRequest, "unlock.html", gin.H{
+			"Action": linkURI,
+			"Error":  "Please enter the password",
+		})
+		return
+	}
+
+	unlock, err := sc.urlService.VerifyURLPassword(c.Request.Host, shortCode, req.Password)
+	if err != nil {
+		if !errors.Is(err, service.ErrInvalidPassword) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Short URL not found or expired",
+			})
+			return
+		}
+		renderPage(c, http.StatusUnauthorized, "unlock.html", gin.H{
+			"Action": linkURI,
+			"Error":  "Incorrect password, please try again",
+		})
+		return
+	}
+
+	// Links without a password need no cookie
+	if unlock == nil {
+		c.Redirect(http.StatusSeeOther, linkURI)
+		return
+	}
+
+	token, err := sc.jwtService.GenerateUnlockToken(unlock.URLID, unlock.PasswordVersion, sc.unlockTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to unlock URL",
+		})
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(unlockCookieName, token, int(sc.unlockTTL.Seconds()), linkPath, "", strings.HasPrefix(sc.baseURL, "https://"), true)
+
+	// Send the browser back to the short link so the redirect is counted as a normal visit
+	c.Redirect(http.StatusSeeOther, linkURI)
+}
+
+// unlockChecker returns the check of the request's unlock cookie against the visited link and its current
+// password, nil when the request has none
+func (sc *ShortenerController) unlockChecker(c *gin.Context) func(unlock *models.URLUnlock) bool {
+	token, err := c.Cookie(unlockCookieName)
+	if err != nil || token == "" {
+		return nil
+	}
+	return func(unlock *models.URLUnlock) bool {
+		return sc.jwtService.ValidateUnlockToken(token, unlock.URLID, unlock.PasswordVersion)
+	}
 }
 
 // GetOriginalURLPublic handles GET /api/v1/redirect/:shortCode - returns original URL as JSON (public, no auth)
 func (sc *ShortenerController) GetOriginalURLPublic(c *gin.Context) {
-	shortCode := c.Param("shortCode")
+	sc.respondOriginalURL(c, shortCodeParam(c), nil)
+}
+
+// UnlockURLPublic handles POST /api/v1/redirect/:shortCode - returns original URL of a password-protected link as JSON
+func (sc *ShortenerController) UnlockURLPublic(c *gin.Context) {
+	shortCode := shortCodeParam(c)
 
-	originalURL, err := sc.urlService.GetOriginalURL(shortCode)
+	var req models.UnlockURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	unlock, err := sc.urlService.VerifyURLPassword(linkDomain(c), shortCode, req.Password)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPassword) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":             err.Error(),
+				"password_required": true,
+			})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Short URL not found or expired",
+		})
+		return
+	}
+
+	sc.respondOriginalURL(c, shortCode, func(current *models.URLUnlock) bool {
+		// The password may have changed since it was checked
+		return unlock != nil && current.URLID == unlock.URLID && current.PasswordVersion == unlock.PasswordVersion
+	})
+}
+
+// respondOriginalURL resolves a short link and writes the destination as JSON
+func (sc *ShortenerController) respondOriginalURL(c *gin.Context, shortCode string, unlocked func(unlock *models.URLUnlock) bool) {
+	target, err := sc.urlService.GetOriginalURL(&models.RedirectRequest{
+		Host:           linkDomain(c),
+		ShortCode:      shortCode,
+		Unlocked:       unlocked,
+		IP:             c.ClientIP(),
+		UserAgent:      c.GetHeader("User-Agent"),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+	})
+	if errors.Is(err, service.ErrPasswordRequired) {
+		// Never reveal the destination of a protected link without the password
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":             "This link is password protected",
+			"password_required": true,
+		})
+		return
+	}
+	if errors.Is(err, repository.ErrURLNotYetActive) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Short URL is not active yet",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Short URL not found or expired",
@@ -91,13 +302,14 @@ func (sc *ShortenerController) GetOriginalURLPublic(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"original_url": originalURL,
+		"original_url":  target.Destination,
+		"redirect_type": target.RedirectType,
 	})
 }
 
 // GetURLStats handles GET /api/v1/url/:shortCode - returns URL statistics
 func (sc *ShortenerController) GetURLStats(c *gin.Context) {
-	shortCode := c.Param("shortCode")
+	shortCode := shortCodeParam(c)
 
 	// Get user ID from JWT context (set by auth middleware) - UUID string
 	userIDStr, exists := c.Get("user_id")
@@ -110,7 +322,7 @@ func (sc *ShortenerController) GetURLStats(c *gin.Context) {
 	}
 	userID := userIDStr.(string)
 
-	stats, err := sc.urlService.GetURLStats(shortCode, &userID)
+	stats, err := sc.urlService.GetURLStats(linkDomain(c), shortCode, &userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "URL not found",
@@ -122,8 +334,9 @@ func (sc *ShortenerController) GetURLStats(c *gin.Context) {
 }
 
 // GetClickAnalytics handles GET /api/v1/url/:shortCode/analytics - returns click analytics
+// (?group_by=variant splits each bucket by A/B destination)
 func (sc *ShortenerController) GetClickAnalytics(c *gin.Context) {
-	shortCode := c.Param("shortCode")
+	shortCode := shortCodeParam(c)
 
 	// Get user ID from JWT context (set by auth middleware) - UUID string
 	userIDStr, exists := c.Get("user_id")
@@ -144,7 +357,41 @@ func (sc *ShortenerController) GetClickAnalytics(c *gin.Context) {
 		}
 	}
 
-	analytics, err := sc.urlService.GetClickAnalytics(shortCode, &userID, hours)
+	analytics, err := sc.urlService.GetClickAnalytics(linkDomain(c), shortCode, &userID, hours, c.Query("group_by"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// GetVariantAnalytics handles GET /api/v1/url/:shortCode/analytics/variants - returns click totals per A/B destination
+func (sc *ShortenerController) GetVariantAnalytics(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	// Get hours parameter (default to 24)
+	hours := 24
+	if hoursStr := c.Query("hours"); hoursStr != "" {
+		if parsedHours, err := strconv.Atoi(hoursStr); err == nil && parsedHours > 0 {
+			hours = parsedHours
+		}
+	}
+
+	analytics, err := sc.urlService.GetVariantAnalytics(linkDomain(c), shortCode, &userID, hours)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": err.Error(),
@@ -157,7 +404,7 @@ func (sc *ShortenerController) GetClickAnalytics(c *gin.Context) {
 
 // DeleteURL handles DELETE /api/v1/url/:shortCode - deletes a URL
 func (sc *ShortenerController) DeleteURL(c *gin.Context) {
-	shortCode := c.Param("shortCode")
+	shortCode := shortCodeParam(c)
 
 	// Get user ID from JWT context (set by auth middleware) - UUID string
 	userIDStr, exists := c.Get("user_id")
@@ -170,7 +417,7 @@ func (sc *ShortenerController) DeleteURL(c *gin.Context) {
 	}
 	userID := userIDStr.(string)
 
-	err := sc.urlService.DeleteURL(shortCode, &userID)
+	err := sc.urlService.DeleteURL(linkDomain(c), shortCode, &userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": err.Error(),
@@ -179,11 +426,52 @@ func (sc *ShortenerController) DeleteURL(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "URL deleted successfully",
+		"message": "URL moved to trash",
 	})
 }
 
-// GetUserURLs handles GET /api/v1/urls - returns all URLs for the authenticated user
+// ArchiveURL handles POST /api/v1/url/:shortCode/archive - hides a URL from the default list without disabling it
+func (sc *ShortenerController) ArchiveURL(c *gin.Context) {
+	sc.setArchived(c, true)
+}
+
+// UnarchiveURL handles DELETE /api/v1/url/:shortCode/archive - moves a URL back to the default list
+func (sc *ShortenerController) UnarchiveURL(c *gin.Context) {
+	sc.setArchived(c, false)
+}
+
+func (sc *ShortenerController) setArchived(c *gin.Context, archived bool) {
+	shortCode := shortCodeParam(c)
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	if err := sc.urlService.ArchiveURL(linkDomain(c), shortCode, userID, archived); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	message := "URL archived successfully"
+	if !archived {
+		message = "URL unarchived successfully"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+	})
+}
+
+// GetUserURLs handles GET /api/v1/urls - returns a page of the URLs of the authenticated user
+// (?archived=true for the archive; see models.URLListQuery for filters, search, sorting and pagination)
 func (sc *ShortenerController) GetUserURLs(c *gin.Context) {
 	// Get user ID from JWT context (set by auth middleware) - UUID string
 	userIDStr, exists := c.Get("user_id")
@@ -196,9 +484,17 @@ func (sc *ShortenerController) GetUserURLs(c *gin.Context) {
 	}
 	userID := userIDStr.(string)
 
-	urls, err := sc.urlService.GetUserURLs(userID)
+	var query models.URLListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	urls, err := sc.urlService.GetUserURLs(userID, &query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(listErrorStatus(err), gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -207,10 +503,47 @@ func (sc *ShortenerController) GetUserURLs(c *gin.Context) {
 	c.JSON(http.StatusOK, urls)
 }
 
-// UpdateURLExpiresAt handles PATCH /api/v1/url/:shortCode - updates expiration date
-func (sc *ShortenerController) UpdateURLExpiresAt(c *gin.Context) {
-	shortCode := c.Param("shortCode")
+// BatchUpdateURLs handles POST /api/v1/urls/batch - applies one action to a list of links
+// or to all links matching a filter, reporting the outcome per link
+func (sc *ShortenerController) BatchUpdateURLs(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := sc.urlService.BatchUpdateURLs(userID, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, repository.ErrFolderNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
 
+	c.JSON(http.StatusOK, response)
+}
+
+// GetUserClickAnalytics handles GET /api/v1/analytics - returns click analytics across the user's links
+// (accepts the filters of GET /api/v1/urls)
+func (sc *ShortenerController) GetUserClickAnalytics(c *gin.Context) {
 	// Get user ID from JWT context (set by auth middleware) - UUID string
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
@@ -222,9 +555,101 @@ func (sc *ShortenerController) UpdateURLExpiresAt(c *gin.Context) {
 	}
 	userID := userIDStr.(string)
 
-	var req struct {
-		ExpiresAt *string `json:"expires_at"` // ISO 8601 string or null
+	var query models.URLListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Get hours parameter (default to 24)
+	hours := 24
+	if hoursStr := c.Query("hours"); hoursStr != "" {
+		if parsedHours, err := strconv.Atoi(hoursStr); err == nil && parsedHours > 0 {
+			hours = parsedHours
+		}
+	}
+
+	analytics, err := sc.urlService.GetUserClickAnalytics(userID, &query, hours)
+	if err != nil {
+		c.JSON(listErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// GetCampaignAnalytics handles GET /api/v1/analytics/campaigns - returns link and click totals per UTM campaign
+// (accepts the filters of GET /api/v1/urls)
+func (sc *ShortenerController) GetCampaignAnalytics(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var query models.URLListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Get hours parameter (default to 24)
+	hours := 24
+	if hoursStr := c.Query("hours"); hoursStr != "" {
+		if parsedHours, err := strconv.Atoi(hoursStr); err == nil && parsedHours > 0 {
+			hours = parsedHours
+		}
+	}
+
+	analytics, err := sc.urlService.GetCampaignAnalytics(userID, &query, hours)
+	if err != nil {
+		c.JSON(listErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// listErrorStatus maps an error from a filtered listing to an HTTP status
+func listErrorStatus(err error) int {
+	if errors.Is(err, repository.ErrFolderNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, service.ErrInvalidCursor) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// UpdateURL handles PATCH /api/v1/url/:shortCode - updates the link's settings
+func (sc *ShortenerController) UpdateURL(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
 	}
+	userID := userIDStr.(string)
+
+	var req models.UpdateURLRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request body",
@@ -233,21 +658,47 @@ func (sc *ShortenerController) UpdateURLExpiresAt(c *gin.Context) {
 		return
 	}
 
-	var expiresAt *time.Time
-	if req.ExpiresAt != nil && *req.ExpiresAt != "" {
-		parsed, err := time.Parse(time.RFC3339, *req.ExpiresAt)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid date format. Use ISO 8601 format (e.g., 2024-12-31T23:59:59Z)",
-			})
-			return
+	err := sc.urlService.UpdateURL(linkDomain(c), shortCode, &userID, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.HasPrefix(err.Error(), "URL not found") {
+			status = http.StatusNotFound
 		}
-		// Ensure the time is in UTC
-		utcTime := parsed.UTC()
-		expiresAt = &utcTime
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
 	}
 
-	err := sc.urlService.UpdateExpiresAt(shortCode, &userID, expiresAt)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "URL updated successfully",
+	})
+}
+
+// GetURLRevisions handles GET /api/v1/url/:shortCode/revisions - lists previous versions of a URL
+func (sc *ShortenerController) GetURLRevisions(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	// Get limit parameter (default to 50, max 200)
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 200 {
+			limit = parsedLimit
+		}
+	}
+
+	revisions, err := sc.urlService.GetURLRevisions(linkDomain(c), shortCode, &userID, limit)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": err.Error(),
@@ -255,8 +706,36 @@ func (sc *ShortenerController) UpdateURLExpiresAt(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "URL expiration updated successfully",
-	})
+	c.JSON(http.StatusOK, revisions)
 }
 
+// RollbackURL handles POST /api/v1/url/:shortCode/revisions/:revisionId/rollback - restores a previous version
+func (sc *ShortenerController) RollbackURL(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+	revisionID := c.Param("revisionId")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	url, err := sc.urlService.RollbackURL(linkDomain(c), shortCode, &userID, revisionID)
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.HasPrefix(err.Error(), "URL not found") || errors.Is(err, repository.ErrRevisionNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, url)
+}
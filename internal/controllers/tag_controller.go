@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagController struct {
+	tagService service.TagService
+}
+
+func NewTagController(tagService service.TagService) *TagController {
+	return &TagController{
+		tagService: tagService,
+	}
+}
+
+// ListTags handles GET /api/v1/tags - returns the tags of the authenticated user
+func (tc *TagController) ListTags(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	tags, err := tc.tagService.ListTags(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// CreateTag handles POST /api/v1/tags - creates a tag
+func (tc *TagController) CreateTag(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tag, err := tc.tagService.CreateTag(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// UpdateTag handles PATCH /api/v1/tags/:tagId - renames or recolors a tag
+func (tc *TagController) UpdateTag(c *gin.Context) {
+	tagID := c.Param("tagId")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tag, err := tc.tagService.UpdateTag(userID, tagID, &req)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag handles DELETE /api/v1/tags/:tagId - removes a tag from every link
+func (tc *TagController) DeleteTag(c *gin.Context) {
+	tagID := c.Param("tagId")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	if err := tc.tagService.DeleteTag(userID, tagID); err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tag deleted successfully",
+	})
+}
+
+// tagErrorStatus maps tag errors to HTTP status codes
+func tagErrorStatus(err error) int {
+	if errors.Is(err, repository.ErrTagNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
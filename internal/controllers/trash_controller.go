@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"net/http"
+
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashController struct {
+	trashService service.TrashService
+}
+
+func NewTrashController(trashService service.TrashService) *TrashController {
+	return &TrashController{
+		trashService: trashService,
+	}
+}
+
+// ListTrash handles GET /api/v1/trash - returns the trashed URLs of the authenticated user
+func (tc *TrashController) ListTrash(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	urls, err := tc.trashService.ListTrash(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, urls)
+}
+
+// RestoreURL handles POST /api/v1/trash/:shortCode/restore - takes a URL out of the trash
+func (tc *TrashController) RestoreURL(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	url, err := tc.trashService.RestoreURL(linkDomain(c), shortCode, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, url)
+}
+
+// PurgeURL handles DELETE /api/v1/trash/:shortCode - permanently deletes a trashed URL and its analytics
+func (tc *TrashController) PurgeURL(c *gin.Context) {
+	shortCode := shortCodeParam(c)
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	if err := tc.trashService.PurgeURL(linkDomain(c), shortCode, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "URL permanently deleted",
+	})
+}
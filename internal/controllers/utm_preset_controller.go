@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+	"shortly-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UTMPresetController struct {
+	presetService service.UTMPresetService
+}
+
+func NewUTMPresetController(presetService service.UTMPresetService) *UTMPresetController {
+	return &UTMPresetController{
+		presetService: presetService,
+	}
+}
+
+// ListPresets handles GET /api/v1/utm-presets - returns the UTM presets of the authenticated user
+func (pc *UTMPresetController) ListPresets(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	presets, err := pc.presetService.ListPresets(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presets)
+}
+
+// CreatePreset handles POST /api/v1/utm-presets - saves a set of UTM parameters under a name
+func (pc *UTMPresetController) CreatePreset(c *gin.Context) {
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.UTMPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	preset, err := pc.presetService.CreatePreset(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, preset)
+}
+
+// UpdatePreset handles PATCH /api/v1/utm-presets/:presetId - renames a preset or changes its parameters
+func (pc *UTMPresetController) UpdatePreset(c *gin.Context) {
+	presetID := c.Param("presetId")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	var req models.UpdateUTMPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	preset, err := pc.presetService.UpdatePreset(userID, presetID, &req)
+	if err != nil {
+		c.JSON(utmPresetErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, preset)
+}
+
+// DeletePreset handles DELETE /api/v1/utm-presets/:presetId - removes a preset (links keep their parameters)
+func (pc *UTMPresetController) DeletePreset(c *gin.Context) {
+	presetID := c.Param("presetId")
+
+	// Get user ID from JWT context (set by auth middleware) - UUID string
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in token",
+		})
+		c.Abort()
+		return
+	}
+	userID := userIDStr.(string)
+
+	if err := pc.presetService.DeletePreset(userID, presetID); err != nil {
+		c.JSON(utmPresetErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "UTM preset deleted successfully",
+	})
+}
+
+// utmPresetErrorStatus maps UTM preset errors to HTTP status codes
+func utmPresetErrorStatus(err error) int {
+	if errors.Is(err, repository.ErrUTMPresetNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WellKnownController struct {
+	appleAppSiteAssociation []byte
+	assetLinks              []byte
+}
+
+// NewWellKnownController serves app association files loaded from configuration.
+// A nil document makes the corresponding endpoint return 404.
+func NewWellKnownController(appleAppSiteAssociation, assetLinks []byte) *WellKnownController {
+	return &WellKnownController{
+		appleAppSiteAssociation: appleAppSiteAssociation,
+		assetLinks:              assetLinks,
+	}
+}
+
+// AppleAppSiteAssociation handles GET /.well-known/apple-app-site-association - iOS universal links
+func (wc *WellKnownController) AppleAppSiteAssociation(c *gin.Context) {
+	wc.serveJSON(c, wc.appleAppSiteAssociation)
+}
+
+// AssetLinks handles GET /.well-known/assetlinks.json - Android app links
+func (wc *WellKnownController) AssetLinks(c *gin.Context) {
+	wc.serveJSON(c, wc.assetLinks)
+}
+
+func (wc *WellKnownController) serveJSON(c *gin.Context, document []byte) {
+	if document == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Not configured",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/json", document)
+}
@@ -0,0 +1,11 @@
+package entities
+
+// DeepLink configures how a short link opens a mobile app.
+// Visitors on iOS or Android are sent to the app URL first and fall back to the
+// store URL (or the regular destination) when the app is not installed.
+type DeepLink struct {
+	IOSURL          string `json:"ios_url,omitempty"`           // Custom scheme or universal link, e.g. myapp://promo
+	IOSStoreURL     string `json:"ios_store_url,omitempty"`     // App Store page
+	AndroidURL      string `json:"android_url,omitempty"`       // Custom scheme, app link or intent:// URL
+	AndroidStoreURL string `json:"android_store_url,omitempty"` // Play Store page
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// Domain is a custom branded hostname that a user can create short links on
+type Domain struct {
+	ID                string     `json:"id"` // UUID
+	UserID            string     `json:"-"`
+	Hostname          string     `json:"hostname"`           // Lowercase, without port, e.g. go.acme.com
+	VerificationToken string     `json:"verification_token"` // Value expected in the DNS TXT record
+	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// IsVerified reports whether DNS ownership of the domain has been confirmed
+func (d *Domain) IsVerified() bool {
+	return d.VerifiedAt != nil
+}
@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// Folder groups links; folders can be nested under a parent folder
+type Folder struct {
+	ID        string    `json:"id"` // UUID
+	UserID    string    `json:"-"`
+	ParentID  *string   `json:"parent_id,omitempty"` // Pointer allows nil (top-level folder)
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job kinds
+const (
+	JobKindBulkCreate = "bulk_create"
+	JobKindImport     = "import"
+)
+
+// Job statuses
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+// Job is a long-running task started through the API; clients poll it until it finishes
+type Job struct {
+	ID         string          `json:"id"` // UUID
+	UserID     string          `json:"-"`
+	Kind       string          `json:"kind"`
+	Status     string          `json:"status"`
+	Total      int             `json:"total"`     // Number of items to process
+	Processed  int             `json:"processed"` // Items processed so far, including failed ones
+	Failed     int             `json:"failed"`
+	Result     json.RawMessage `json:"result,omitempty"` // Kind-specific outcome, set once the job completes
+	Error      *string         `json:"error,omitempty"`  // Why the job failed as a whole
+	CreatedAt  time.Time       `json:"created_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
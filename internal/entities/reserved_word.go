@@ -0,0 +1,10 @@
+package entities
+
+import "time"
+
+// ReservedWord is a word admins reserved so it can't be used as a short code
+type ReservedWord struct {
+	Word      string    `json:"word"` // Lowercase
+	CreatedBy *string   `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// RoutingRule sends visitors matching all of its conditions to an alternative destination
+type RoutingRule struct {
+	ID          string         `json:"id"` // UUID
+	URLID       string         `json:"-"`
+	Position    int            `json:"position"` // Rules are evaluated in ascending position
+	Destination string         `json:"destination"`
+	Conditions  RuleConditions `json:"conditions"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// RuleConditions are the visitor attributes a rule matches on.
+// Empty fields match everyone; a rule matches when every non-empty field matches.
+type RuleConditions struct {
+	Countries        []string `json:"countries,omitempty"`    // ISO 3166-1 alpha-2 codes, e.g. "DE"
+	Devices          []string `json:"devices,omitempty"`      // desktop, mobile, tablet, bot
+	OperatingSystems []string `json:"os,omitempty"`           // ios, android, windows, macos, linux, chromeos, other
+	Languages        []string `json:"languages,omitempty"`    // BCP 47 tags matched against the preferred Accept-Language, e.g. "de" or "en-US"
+	DaysOfWeek       []int    `json:"days_of_week,omitempty"` // 0 = Sunday ... 6 = Saturday
+	StartTime        string   `json:"start_time,omitempty"`   // "HH:MM", inclusive
+	EndTime          string   `json:"end_time,omitempty"`     // "HH:MM", exclusive; may wrap past midnight
+	Timezone         string   `json:"timezone,omitempty"`     // IANA zone for day and time checks, defaults to UTC
+}
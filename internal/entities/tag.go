@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// Tag is a user-defined label that can be attached to any number of links
+type Tag struct {
+	ID        string    `json:"id"` // UUID
+	UserID    string    `json:"-"`
+	Name      string    `json:"name"`
+	Color     *string   `json:"color,omitempty"` // Hex color, e.g. #ff8800
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,15 +1,61 @@
 package entities
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
 
 // URL represents a shortened URL entity in the database
 type URL struct {
-	ID          string     `json:"id"` // UUID
-	ShortCode   string     `json:"short_code"`
-	OriginalURL string     `json:"original_url"`
-	UserID      *string    `json:"user_id,omitempty"` // Pointer allows nil (for anonymous URLs), UUID
-	ClickCount  int        `json:"click_count"`
-	CreatedAt   time.Time  `json:"created_at"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // Pointer allows nil (no expiration)
+	ID           string     `json:"id"`               // UUID
+	Domain       string     `json:"domain,omitempty"` // Custom domain hostname, empty for the default domain
+	ShortCode    string     `json:"short_code"`
+	OriginalURL  string     `json:"original_url"`
+	UserID       *string    `json:"user_id,omitempty"` // Pointer allows nil (for anonymous URLs), UUID
+	ClickCount   int        `json:"click_count"`
+	CreatedAt    time.Time  `json:"created_at"`
+	StartsAt     *time.Time `json:"starts_at,omitempty"`  // Pointer allows nil (active immediately)
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"` // Pointer allows nil (no expiration)
+	RedirectType int        `json:"redirect_type"`        // HTTP status used for the redirect (301, 302, 307, 308)
+	PasswordHash *string    `json:"-"`                    // bcrypt hash, nil when the link is not password protected
+	MaxClicks    *int       `json:"max_clicks,omitempty"` // Pointer allows nil (unlimited clicks)
+
+	StickyVariants bool             `json:"sticky_variants"`        // Keep returning visitors on the same destination
+	Destinations   []URLDestination `json:"destinations,omitempty"` // Weighted A/B destinations, loaded separately
+	DeepLink       *DeepLink        `json:"deep_link,omitempty"`    // Pointer allows nil (no app deep linking)
+
+	ForwardQuery    bool   `json:"forward_query"`    // Merge the visitor's query parameters into the destination
+	QueryPrecedence string `json:"query_precedence"` // Which side wins on conflicting parameters ("destination" or "incoming")
+	ForwardPath     bool   `json:"forward_path"`     // Append trailing path segments (/:shortCode/*rest) to the destination
+
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`  // Set while the link is in the trash
+	ArchivedAt *time.Time `json:"archived_at,omitempty"` // Set while the link is archived (still redirects)
+
+	Title    *string                `json:"title,omitempty"`
+	Notes    *string                `json:"notes,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`  // Free-form JSON object
+	FolderID *string                `json:"folder_id,omitempty"` // Pointer allows nil (not in a folder)
+	Tags     []Tag                  `json:"tags,omitempty"`      // Loaded separately
 }
 
+// Query precedence values for forwarded query parameters
+const (
+	QueryPrecedenceDestination = "destination" // The destination's own parameters win
+	QueryPrecedenceIncoming    = "incoming"    // The visitor's parameters override the destination's
+)
+
+// IsPasswordProtected reports whether visitors must enter a password before redirecting
+func (u *URL) IsPasswordProtected() bool {
+	return u.PasswordHash != nil && *u.PasswordHash != ""
+}
+
+// PasswordVersion identifies the link's current password, empty when it has none. Every new hash has
+// its own salt, so the version changes whenever the password is set, even to the same value.
+func (u *URL) PasswordVersion() string {
+	if !u.IsPasswordProtected() {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(*u.PasswordHash))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// URLClick is a single recorded visit to a short link
+type URLClick struct {
+	ID           string    `json:"id"` // UUID
+	URLID        string    `json:"url_id"`
+	ClickedAt    time.Time `json:"clicked_at"`
+	VariantID    *string   `json:"variant_id,omitempty"`    // A/B destination served, nil when the link has none
+	VariantLabel *string   `json:"variant_label,omitempty"` // Label of that destination, nil once it has been removed
+}
@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// URLDestination is one weighted variant of an A/B tested short link
+type URLDestination struct {
+	ID        string    `json:"id"` // UUID
+	URLID     string    `json:"-"`
+	Position  int       `json:"position"`
+	URL       string    `json:"url"`
+	Weight    int       `json:"weight"` // Relative share of traffic, e.g. 70 and 30
+	Label     *string   `json:"label,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+// URLRevision is a previous version of a short link, saved before each update
+type URLRevision struct {
+	ID                string                 `json:"id"` // UUID
+	URLID             string                 `json:"-"`
+	ChangedBy         *string                `json:"changed_by,omitempty"` // User who made the change that replaced this version
+	OriginalURL       string                 `json:"original_url"`
+	StartsAt          *time.Time             `json:"starts_at,omitempty"`
+	ExpiresAt         *time.Time             `json:"expires_at,omitempty"`
+	RedirectType      int                    `json:"redirect_type"`
+	PasswordHash      *string                `json:"-"`
+	PasswordProtected bool                   `json:"password_protected"`
+	MaxClicks         *int                   `json:"max_clicks,omitempty"`
+	StickyVariants    bool                   `json:"sticky_variants"`
+	DeepLink          *DeepLink              `json:"deep_link,omitempty"`
+	ForwardQuery      bool                   `json:"forward_query"`
+	QueryPrecedence   string                 `json:"query_precedence"`
+	ForwardPath       bool                   `json:"forward_path"`
+	Destinations      []URLDestination       `json:"destinations,omitempty"`
+	Title             *string                `json:"title,omitempty"`
+	Notes             *string                `json:"notes,omitempty"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	FolderID          *string                `json:"folder_id,omitempty"` // Nil when unfiled or the folder was deleted since
+	Tags              []string               `json:"tags"`                // Tag names
+	CreatedAt         time.Time              `json:"created_at"`          // When this version was replaced
+}
@@ -4,11 +4,16 @@ import "time"
 
 // User represents a user entity in the database
 type User struct {
-	ID           string    `json:"id"` // UUID
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"` // Don't expose password hash in JSON
-	Name         *string   `json:"name,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                  string    `json:"id"` // UUID
+	Email               string    `json:"email"`
+	PasswordHash        string    `json:"-"` // Don't expose password hash in JSON
+	Name                *string   `json:"name,omitempty"`
+	DefaultRedirectType int       `json:"default_redirect_type"` // Redirect status applied to new links, 302 unless changed
+	TrackingParams      []string  `json:"tracking_params"`       // Query parameters stripped from new links ("utm_*" matches a prefix), nil for the deployment default
+	StripFragments      bool      `json:"strip_fragments"`       // Remove #fragments from new links
+	CodeStrategy        *string   `json:"code_strategy"`         // Strategy for generated short codes, nil for the deployment default
+	IsAdmin             bool      `json:"is_admin"`              // Manages deployment-wide settings such as reserved words
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
 }
 
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// UTMPreset is a saved set of UTM parameters that can be applied to new links
+type UTMPreset struct {
+	ID        string    `json:"id"` // UUID
+	UserID    string    `json:"-"`
+	Name      string    `json:"name"`
+	Source    string    `json:"source,omitempty"` // Empty values are not applied
+	Medium    string    `json:"medium,omitempty"`
+	Campaign  string    `json:"campaign,omitempty"`
+	Term      string    `json:"term,omitempty"`
+	Content   string    `json:"content,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
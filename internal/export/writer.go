@@ -0,0 +1,213 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported export formats
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// flushEvery is the number of records buffered before they are sent to the client
+const flushEvery = 500
+
+// Writer streams records in an export format. Values are given in the order of the writer's columns.
+type Writer interface {
+	Write(values ...interface{}) error
+	// Close writes any trailing output and flushes everything to the client
+	Close() error
+}
+
+// ContentType returns the MIME type of an export format
+func ContentType(format string) string {
+	switch format {
+	case FormatJSON:
+		return "application/json"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// NewWriter creates a writer producing format on w with the given columns
+func NewWriter(format string, w io.Writer, columns []string) (Writer, error) {
+	out := &flusher{dest: w, buf: bufio.NewWriter(w)}
+	switch format {
+	case FormatCSV:
+		csvWriter := csv.NewWriter(out.buf)
+		if err := csvWriter.Write(columns); err != nil {
+			return nil, err
+		}
+		out.beforeFlush = csvWriter.Flush
+		return &csvExport{out: out, csv: csvWriter}, nil
+	case FormatJSON:
+		return &jsonExport{out: out, columns: columns, array: true}, nil
+	case FormatNDJSON:
+		return &jsonExport{out: out, columns: columns}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format '%s'", format)
+}
+
+// flusher buffers output and periodically pushes it to the client
+type flusher struct {
+	dest        io.Writer
+	buf         *bufio.Writer
+	beforeFlush func() // Moves output buffered by an encoder into buf
+	records     int
+}
+
+// record counts a written record and flushes every flushEvery records
+func (f *flusher) record() error {
+	f.records++
+	if f.records%flushEvery == 0 {
+		return f.flush()
+	}
+	return nil
+}
+
+// flush sends the buffered output, flushing the HTTP response when streaming to one
+func (f *flusher) flush() error {
+	if f.beforeFlush != nil {
+		f.beforeFlush()
+	}
+	if err := f.buf.Flush(); err != nil {
+		return err
+	}
+	if httpFlusher, ok := f.dest.(http.Flusher); ok {
+		httpFlusher.Flush()
+	}
+	return nil
+}
+
+type csvExport struct {
+	out *flusher
+	csv *csv.Writer
+}
+
+func (e *csvExport) Write(values ...interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = csvValue(value)
+	}
+	if err := e.csv.Write(record); err != nil {
+		return err
+	}
+	return e.out.record()
+}
+
+func (e *csvExport) Close() error {
+	if err := e.out.flush(); err != nil {
+		return err
+	}
+	return e.csv.Error()
+}
+
+// csvValue formats a value for a CSV cell: empty for nil, RFC 3339 for times,
+// semicolon-separated for string lists and JSON for anything structured
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case int:
+		return strconv.Itoa(v)
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ";")
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil || string(encoded) == "null" {
+		return ""
+	}
+	return string(encoded)
+}
+
+// jsonExport writes records as JSON objects keyed by column, either as one array or one object per line
+type jsonExport struct {
+	out     *flusher
+	columns []string
+	array   bool
+	started bool
+}
+
+func (e *jsonExport) Write(values ...interface{}) error {
+	separator := "\n"
+	if e.array {
+		separator = ",\n"
+		if !e.started {
+			separator = "[\n"
+		}
+	} else if !e.started {
+		separator = ""
+	}
+	e.started = true
+
+	var object strings.Builder
+	object.WriteString(separator + "{")
+	for i, column := range e.columns {
+		if i > 0 {
+			object.WriteString(",")
+		}
+		key, _ := json.Marshal(column)
+		encoded, err := json.Marshal(values[i])
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", column, err)
+		}
+		object.Write(key)
+		object.WriteString(":")
+		object.Write(encoded)
+	}
+	object.WriteString("}")
+
+	if _, err := e.out.buf.WriteString(object.String()); err != nil {
+		return err
+	}
+	return e.out.record()
+}
+
+func (e *jsonExport) Close() error {
+	var trailer string
+	switch {
+	case e.array && e.started:
+		trailer = "\n]\n"
+	case e.array:
+		trailer = "[]\n"
+	case e.started:
+		trailer = "\n"
+	}
+	if _, err := e.out.buf.WriteString(trailer); err != nil {
+		return err
+	}
+	return e.out.flush()
+}
@@ -0,0 +1,54 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Resolver looks up the country of a visitor's IP address
+type Resolver interface {
+	// Country returns the ISO 3166-1 alpha-2 country code (e.g. "DE"), or "" if unknown
+	Country(ip net.IP) (string, error)
+	Close() error
+}
+
+type mmdbResolver struct {
+	reader *maxminddb.Reader
+}
+
+// countryRecord holds the fields we read from GeoLite2/GeoIP2 Country or City databases
+type countryRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+// NewMMDBResolver opens a local MaxMind .mmdb database (GeoLite2-Country or compatible)
+func NewMMDBResolver(path string) (Resolver, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database: %w", err)
+	}
+	return &mmdbResolver{reader: reader}, nil
+}
+
+// Country returns the country code for an IP address
+func (r *mmdbResolver) Country(ip net.IP) (string, error) {
+	if ip == nil {
+		return "", nil
+	}
+
+	var record countryRecord
+	if err := r.reader.Lookup(ip, &record); err != nil {
+		return "", fmt.Errorf("failed to look up IP: %w", err)
+	}
+	return strings.ToUpper(record.Country.ISOCode), nil
+}
+
+// Close releases the database file
+func (r *mmdbResolver) Close() error {
+	return r.reader.Close()
+}
@@ -0,0 +1,106 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// csvColumns maps the header names used by shortener exports to the field they hold
+var csvColumns = map[string]string{
+	"url":             "url",
+	"long_url":        "url",
+	"original_url":    "url",
+	"destination":     "url",
+	"destination_url": "url",
+	"short_code":      "code",
+	"code":            "code",
+	"keyword":         "code",
+	"alias":           "code",
+	"back_half":       "code",
+	"link":            "code",
+	"bitlink":         "code",
+	"short_url":       "code",
+	"shorturl":        "code",
+	"tinyurl":         "code",
+	"title":           "title",
+	"tags":            "tags",
+	"clicks":          "clicks",
+	"total_clicks":    "clicks",
+	"click_count":     "clicks",
+	"hits":            "clicks",
+	"created":         "created",
+	"created_at":      "created",
+	"creation_date":   "created",
+	"date":            "created",
+	"timestamp":       "created",
+}
+
+// normalizeColumn turns a header such as "Long URL" into "long_url"
+func normalizeColumn(name string) string {
+	name = strings.ToLower(strings.TrimPrefix(name, "\ufeff")) // Spreadsheet exports may start with a byte order mark
+	return strings.Join(strings.Fields(strings.NewReplacer("-", " ", "_", " ").Replace(name)), "_")
+}
+
+// parseCSV reads a CSV export with a header row. Columns are recognized by name, unknown ones are ignored.
+func parseCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if field, ok := csvColumns[normalizeColumn(name)]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["url"]; !ok {
+		return nil, fmt.Errorf("CSV header must include a url column (e.g. url or long_url)")
+	}
+
+	var records []Record
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		value := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+
+		record := Record{
+			Row:       len(records) + 1,
+			ShortCode: shortCodeFrom(value("code")),
+			URL:       value("url"),
+			Title:     value("title"),
+			Tags:      splitTags(value("tags")),
+			Clicks:    parseClicks(value("clicks")),
+			CreatedAt: parseDate(value("created")),
+		}
+		if record.URL == "" {
+			record.Error = "missing url"
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
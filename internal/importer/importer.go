@@ -0,0 +1,115 @@
+// Package importer reads link exports of other URL shorteners (Bitly, TinyURL, YOURLS and generic CSV)
+package importer
+
+import (
+	"fmt"
+	"io"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported export formats
+const (
+	FormatBitly      = "bitly"       // Bitly CSV export
+	FormatTinyURL    = "tinyurl"     // TinyURL CSV export
+	FormatCSV        = "csv"         // Any CSV with a header naming at least a url column
+	FormatYOURLSJSON = "yourls_json" // YOURLS stats API output or a JSON dump of the url table
+	FormatYOURLSSQL  = "yourls_sql"  // mysqldump of the YOURLS url table
+)
+
+// Record is one link read from an export
+type Record struct {
+	Row       int    // 1-based position of the link in the export
+	ShortCode string // Code on the previous shortener, empty when the export has none
+	URL       string
+	Title     string
+	Tags      []string
+	Clicks    int        // Historic click total
+	CreatedAt *time.Time // Creation time on the previous shortener, nil when unknown
+	Error     string     // Set when the link could not be read
+}
+
+// Parse reads all links of an export in the given format
+func Parse(format string, r io.Reader) ([]Record, error) {
+	switch format {
+	case FormatBitly, FormatTinyURL, FormatCSV:
+		return parseCSV(r)
+	case FormatYOURLSJSON:
+		return parseYOURLSJSON(r)
+	case FormatYOURLSSQL:
+		return parseYOURLSSQL(r)
+	}
+	return nil, fmt.Errorf("unsupported import format '%s'", format)
+}
+
+// shortCodeFrom extracts the code from a short link ("https://bit.ly/3abc", "bit.ly/3abc") or returns a bare code as-is
+func shortCodeFrom(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "/") {
+		return value
+	}
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+	u, err := neturl.Parse(value)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	return segments[len(segments)-1]
+}
+
+// parseClicks reads a click total such as "1,024", returning 0 for anything else
+func parseClicks(value string) int {
+	clicks, err := strconv.Atoi(strings.ReplaceAll(strings.TrimSpace(value), ",", ""))
+	if err != nil || clicks < 0 {
+		return 0
+	}
+	return clicks
+}
+
+// dateLayouts are the timestamp formats found in shortener exports
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"01/02/2006",
+}
+
+// parseDate reads a creation timestamp, assuming UTC when it has no zone; nil when unrecognized
+func parseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			parsed = parsed.UTC()
+			return &parsed
+		}
+	}
+	// Unix timestamps
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+		parsed := time.Unix(seconds, 0).UTC()
+		return &parsed
+	}
+	return nil
+}
+
+// splitTags splits a tag list separated by commas or semicolons
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
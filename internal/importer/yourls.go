@@ -0,0 +1,232 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// yourlsColumns is the column order of the YOURLS url table, used when a dump's INSERT has no column list
+var yourlsColumns = []string{"keyword", "url", "title", "timestamp", "ip", "clicks"}
+
+// yourlsRecord builds a record from a row of the YOURLS url table (or an entry of its stats API)
+func yourlsRecord(row int, fields map[string]string) Record {
+	code := fields["keyword"]
+	if code == "" {
+		code = shortCodeFrom(fields["shorturl"])
+	}
+	record := Record{
+		Row:       row,
+		ShortCode: code,
+		URL:       strings.TrimSpace(fields["url"]),
+		Title:     strings.TrimSpace(fields["title"]),
+		Clicks:    parseClicks(fields["clicks"]),
+		CreatedAt: parseDate(fields["timestamp"]),
+	}
+	if record.URL == "" {
+		record.Error = "missing url"
+	}
+	return record
+}
+
+// parseYOURLSJSON reads either the output of the YOURLS stats API ({"links": {"link_1": {...}}})
+// or a JSON array of url table rows
+func parseYOURLSJSON(r io.Reader) ([]Record, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		var stats struct {
+			Links map[string]map[string]interface{} `json:"links"`
+		}
+		if err := json.Unmarshal(raw, &stats); err != nil || stats.Links == nil {
+			return nil, fmt.Errorf("expected a JSON array of links or a YOURLS stats response")
+		}
+		// Keep the API's link_1, link_2, ... order
+		keys := make([]string, 0, len(stats.Links))
+		for key := range stats.Links {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, _ := strconv.Atoi(strings.TrimPrefix(keys[i], "link_"))
+			b, _ := strconv.Atoi(strings.TrimPrefix(keys[j], "link_"))
+			return a < b
+		})
+		for _, key := range keys {
+			rows = append(rows, stats.Links[key])
+		}
+	}
+
+	records := make([]Record, len(rows))
+	for i, row := range rows {
+		fields := make(map[string]string, len(row))
+		for key, value := range row {
+			switch v := value.(type) {
+			case string:
+				fields[strings.ToLower(key)] = v
+			case float64:
+				fields[strings.ToLower(key)] = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+		}
+		records[i] = yourlsRecord(i+1, fields)
+	}
+	return records, nil
+}
+
+// insertPattern finds the INSERT statements of a SQL dump along with their table and optional column list
+var insertPattern = regexp.MustCompile("(?is)INSERT\\s+(?:IGNORE\\s+)?INTO\\s+`?(\\w+)`?\\s*(?:\\(([^)]*)\\))?\\s*VALUES\\s*")
+
+// parseYOURLSSQL reads the rows inserted into the YOURLS url table (yourls_url, or <prefix>_url) by a mysqldump
+func parseYOURLSSQL(r io.Reader) ([]Record, error) {
+	dump, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	sql := string(dump)
+
+	var records []Record
+	for _, match := range insertPattern.FindAllStringSubmatchIndex(sql, -1) {
+		table := strings.ToLower(sql[match[2]:match[3]])
+		if table != "url" && !strings.HasSuffix(table, "_url") {
+			continue
+		}
+
+		columns := yourlsColumns
+		if match[4] >= 0 {
+			columns = nil
+			for _, column := range strings.Split(sql[match[4]:match[5]], ",") {
+				columns = append(columns, strings.ToLower(strings.Trim(strings.TrimSpace(column), "`\"")))
+			}
+		}
+
+		tuples, err := parseSQLTuples(sql[match[1]:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid INSERT into %s: %w", table, err)
+		}
+		for _, values := range tuples {
+			fields := make(map[string]string, len(columns))
+			for i, column := range columns {
+				if i < len(values) {
+					fields[column] = values[i]
+				}
+			}
+			records = append(records, yourlsRecord(len(records)+1, fields))
+		}
+	}
+
+	if records == nil {
+		return nil, fmt.Errorf("no INSERT statements for the YOURLS url table found")
+	}
+	return records, nil
+}
+
+// parseSQLTuples reads the value tuples of an INSERT statement, "('a', 1, NULL), (...)", up to the closing semicolon.
+// Quoted strings support MySQL's backslash escapes and doubled quotes; NULL becomes an empty string.
+func parseSQLTuples(sql string) ([][]string, error) {
+	var tuples [][]string
+	i := 0
+	skipSpace := func() {
+		for i < len(sql) && strings.ContainsRune(" \t\r\n", rune(sql[i])) {
+			i++
+		}
+	}
+
+	for {
+		skipSpace()
+		if i >= len(sql) || sql[i] != '(' {
+			return nil, fmt.Errorf("expected '(' at offset %d", i)
+		}
+		i++
+
+		var values []string
+		for {
+			skipSpace()
+			if i >= len(sql) {
+				return nil, fmt.Errorf("unterminated tuple")
+			}
+
+			var value strings.Builder
+			if sql[i] == '\'' || sql[i] == '"' {
+				quote := sql[i]
+				i++
+				for {
+					if i >= len(sql) {
+						return nil, fmt.Errorf("unterminated string")
+					}
+					c := sql[i]
+					if c == '\\' && i+1 < len(sql) {
+						value.WriteByte(unescapeSQL(sql[i+1]))
+						i += 2
+						continue
+					}
+					if c == quote {
+						if i+1 < len(sql) && sql[i+1] == quote {
+							value.WriteByte(quote)
+							i += 2
+							continue
+						}
+						i++
+						break
+					}
+					value.WriteByte(c)
+					i++
+				}
+				values = append(values, value.String())
+			} else {
+				start := i
+				for i < len(sql) && sql[i] != ',' && sql[i] != ')' {
+					i++
+				}
+				bare := strings.TrimSpace(sql[start:i])
+				if strings.EqualFold(bare, "NULL") {
+					bare = ""
+				}
+				values = append(values, bare)
+			}
+
+			skipSpace()
+			if i >= len(sql) {
+				return nil, fmt.Errorf("unterminated tuple")
+			}
+			if sql[i] == ',' {
+				i++
+				continue
+			}
+			if sql[i] == ')' {
+				i++
+				break
+			}
+			return nil, fmt.Errorf("unexpected '%c' at offset %d", sql[i], i)
+		}
+		tuples = append(tuples, values)
+
+		skipSpace()
+		if i < len(sql) && sql[i] == ',' {
+			i++
+			continue
+		}
+		return tuples, nil
+	}
+}
+
+// unescapeSQL returns the character a MySQL backslash escape stands for
+func unescapeSQL(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case '0':
+		return 0
+	}
+	return c
+}
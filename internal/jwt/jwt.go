@@ -14,6 +14,15 @@ type JWTClaims struct {
 	jwt.RegisteredClaims
 }
 
+// unlockClaims represents the claims of a token unlocking a password-protected short link
+type unlockClaims struct {
+	PasswordVersion string `json:"pwv"` // Password the token was issued for; changing it invalidates the token
+	jwt.RegisteredClaims
+}
+
+// unlockAudience marks tokens that unlock a password-protected short link
+const unlockAudience = "url-unlock"
+
 // JWTService handles JWT token generation and validation
 type JWTService struct {
 	secretKey []byte
@@ -58,10 +67,41 @@ func (j *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+	// Link unlock tokens carry no user and must never authenticate a request
+	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid && claims.UserID != "" {
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
 
+// GenerateUnlockToken generates a short-lived token proving the password for a short link was entered.
+// The subject is the link's ID, which also tells apart links with the same code on different domains.
+func (j *JWTService) GenerateUnlockToken(urlID, passwordVersion string, ttl time.Duration) (string, error) {
+	claims := unlockClaims{
+		PasswordVersion: passwordVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   urlID,
+			Audience:  jwt.ClaimStrings{unlockAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(j.secretKey)
+}
+
+// ValidateUnlockToken reports whether tokenString unlocks the link with the given ID and current password
+func (j *JWTService) ValidateUnlockToken(tokenString, urlID, passwordVersion string) bool {
+	claims := &unlockClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		// Validate signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+		return j.secretKey, nil
+	}, jwt.WithAudience(unlockAudience), jwt.WithSubject(urlID))
+
+	return err == nil && token.Valid && claims.PasswordVersion == passwordVersion
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"shortly-be/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware only lets admins through; it must run after AuthMiddleware
+func AdminMiddleware(userRepo repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "User ID not found in token",
+			})
+			c.Abort()
+			return
+		}
+
+		// Looked up on every request so revoking admin rights takes effect without waiting for tokens to expire
+		user, err := userRepo.FindByID(userID.(string))
+		if err != nil || !user.IsAdmin {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Admin access required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
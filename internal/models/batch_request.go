@@ -0,0 +1,47 @@
+package models
+
+// Actions accepted by POST /api/v1/urls/batch
+const (
+	BatchActionDelete        = "delete"
+	BatchActionArchive       = "archive"
+	BatchActionUnarchive     = "unarchive"
+	BatchActionSetExpiry     = "set_expiry"
+	BatchActionAddTag        = "add_tag"
+	BatchActionRemoveTag     = "remove_tag"
+	BatchActionMoveFolder    = "move_folder"
+	BatchActionTransferOwner = "transfer_owner"
+)
+
+// BatchLink identifies one link of a batch action
+type BatchLink struct {
+	Domain    string `json:"domain,omitempty"` // Custom domain, empty for the default domain
+	ShortCode string `json:"short_code" binding:"required"`
+}
+
+// BatchRequest represents the request body for POST /api/v1/urls/batch.
+// The action applies either to the listed links or to every link matching the filter.
+type BatchRequest struct {
+	Action string        `json:"action" binding:"required,oneof=delete archive unarchive set_expiry add_tag remove_tag move_folder transfer_owner"`
+	Links  []BatchLink   `json:"links,omitempty" binding:"omitempty,max=1000,dive"`
+	Filter *URLListQuery `json:"filter,omitempty"` // Same filters as GET /api/v1/urls
+
+	ExpiresAt     *string `json:"expires_at,omitempty"`                                // set_expiry: ISO 8601 string, null or "" removes the expiration
+	Tag           string  `json:"tag,omitempty" binding:"max=50"`                      // add_tag and remove_tag
+	FolderID      *string `json:"folder_id,omitempty"`                                 // move_folder: null moves the links out of their folder
+	NewOwnerEmail string  `json:"new_owner_email,omitempty" binding:"omitempty,email"` // transfer_owner
+}
+
+// BatchItemResult is the outcome of a batch action for one link
+type BatchItemResult struct {
+	Domain    string `json:"domain,omitempty"`
+	ShortCode string `json:"short_code"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BatchResponse reports the outcome of a batch action for every link it applied to
+type BatchResponse struct {
+	Action    string            `json:"action"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
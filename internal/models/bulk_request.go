@@ -0,0 +1,34 @@
+package models
+
+// BulkCreateRow is one link of a bulk creation (POST /api/v1/shorten/bulk)
+type BulkCreateRow struct {
+	Row     int // 1-based position in the upload; CSV rows are counted without the header
+	Request *CreateURLRequest
+	Error   string // Set when the row was rejected while parsing; it is then reported as failed
+}
+
+// BulkCreateResult is the outcome of one row of a bulk creation
+type BulkCreateResult struct {
+	Row   int                `json:"row"`
+	URL   *CreateURLResponse `json:"url,omitempty"`
+	Error string             `json:"error,omitempty"`
+}
+
+// BulkCreateResponse reports the outcome of every row of a bulk creation
+type BulkCreateResponse struct {
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Results []BulkCreateResult `json:"results"`
+}
+
+// Add appends results and updates the totals
+func (r *BulkCreateResponse) Add(results []BulkCreateResult) {
+	for _, result := range results {
+		if result.Error != "" {
+			r.Failed++
+		} else {
+			r.Created++
+		}
+	}
+	r.Results = append(r.Results, results...)
+}
@@ -0,0 +1,6 @@
+package models
+
+// AddDomainRequest represents the request body for registering a custom domain
+type AddDomainRequest struct {
+	Hostname string `json:"hostname" binding:"required,max=253"` // e.g. go.acme.com
+}
@@ -0,0 +1,59 @@
+package models
+
+// Import conflict policies: what happens to a link whose original short code can't be kept
+const (
+	ImportOnConflictSkip     = "skip"     // Leave the link out and report the conflict
+	ImportOnConflictGenerate = "generate" // Import the link with a generated code
+)
+
+// Outcomes of an imported link
+const (
+	ImportStatusCreated = "created" // Imported, keeping its original code when it had one
+	ImportStatusRenamed = "renamed" // Imported with a generated code because the original one conflicted
+	ImportStatusSkipped = "skipped" // Left out because its original code conflicted
+	ImportStatusFailed  = "failed"
+)
+
+// ImportOptions are the query parameters of POST /api/v1/import
+type ImportOptions struct {
+	Format       string  `form:"format" binding:"required,oneof=bitly tinyurl csv yourls_json yourls_sql"`
+	Domain       *string `form:"domain"`                                              // Verified custom domain to import into, defaults to BASE_URL
+	ImportClicks bool    `form:"import_clicks"`                                       // Carry over historic click totals
+	OnConflict   string  `form:"on_conflict" binding:"omitempty,oneof=skip generate"` // Defaults to "skip"
+}
+
+// ImportResult is the outcome of one link of an import
+type ImportResult struct {
+	Row          int    `json:"row"`
+	OriginalCode string `json:"original_code,omitempty"` // Code on the previous shortener
+	ShortCode    string `json:"short_code,omitempty"`
+	ShortURL     string `json:"short_url,omitempty"`
+	Status       string `json:"status"`
+	Reason       string `json:"reason,omitempty"` // Why the link was renamed, skipped or failed
+}
+
+// ImportResponse reports the outcome of every link of an import
+type ImportResponse struct {
+	Created int            `json:"created"`
+	Renamed int            `json:"renamed"`
+	Skipped int            `json:"skipped"`
+	Failed  int            `json:"failed"`
+	Results []ImportResult `json:"results"`
+}
+
+// Add appends results and updates the totals
+func (r *ImportResponse) Add(results []ImportResult) {
+	for _, result := range results {
+		switch result.Status {
+		case ImportStatusCreated:
+			r.Created++
+		case ImportStatusRenamed:
+			r.Renamed++
+		case ImportStatusSkipped:
+			r.Skipped++
+		default:
+			r.Failed++
+		}
+	}
+	r.Results = append(r.Results, results...)
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Optional distinguishes a JSON field that was omitted from one explicitly set to null.
+// PATCH requests use it so that absent fields are left unchanged while null clears them.
+type Optional[T any] struct {
+	Set   bool // true when the field was present in the request body
+	Value *T   // nil when the field was present but null
+}
+
+// UnmarshalJSON records that the field was present and decodes its value
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		o.Value = nil
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	o.Value = &value
+	return nil
+}
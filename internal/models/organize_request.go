@@ -0,0 +1,25 @@
+package models
+
+// TagRequest represents the request body for creating a tag
+type TagRequest struct {
+	Name  string  `json:"name" binding:"required,max=50"`
+	Color *string `json:"color,omitempty" binding:"omitempty,hexcolor"`
+}
+
+// UpdateTagRequest represents the request body for PATCH /api/v1/tags/:tagId
+type UpdateTagRequest struct {
+	Name  *string          `json:"name" binding:"omitempty,min=1,max=50"`
+	Color Optional[string] `json:"color"` // null removes the color
+}
+
+// FolderRequest represents the request body for creating a folder
+type FolderRequest struct {
+	Name     string  `json:"name" binding:"required,max=100"`
+	ParentID *string `json:"parent_id,omitempty"` // Omit for a top-level folder
+}
+
+// UpdateFolderRequest represents the request body for PATCH /api/v1/folders/:folderId
+type UpdateFolderRequest struct {
+	Name     *string          `json:"name" binding:"omitempty,min=1,max=100"`
+	ParentID Optional[string] `json:"parent_id"` // null moves the folder to the top level
+}
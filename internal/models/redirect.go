@@ -0,0 +1,34 @@
+package models
+
+// RedirectRequest describes an incoming visit to a short link
+type RedirectRequest struct {
+	Host           string // Host header, selects the custom domain the short code belongs to
+	ShortCode      string
+	Unlocked       func(unlock *URLUnlock) bool // Reports whether the visitor unlocked the link, nil when they didn't try
+	IP             string                       // Client IP, used for country routing
+	UserAgent      string
+	AcceptLanguage string
+	VariantID      string // Destination remembered in the visitor's sticky variant cookie, if any
+	Path           string // Trailing path after the short code (/:shortCode/*rest), empty for none
+	RawQuery       string // Incoming query string, without the leading "?"
+}
+
+// URLUnlock identifies the link and password an unlock token was issued for
+type URLUnlock struct {
+	URLID           string // Unique across domains, so a token for one domain's link can't unlock another's
+	PasswordVersion string // Changes with the link's password, invalidating earlier unlocks
+}
+
+// RedirectTarget is the resolved destination for a visit to a short link
+type RedirectTarget struct {
+	ShortCode    string
+	Destination  string
+	RedirectType int
+	RuleID       *string // Routing rule that selected the destination, nil for the default
+	VariantID    *string // A/B destination that was served, nil when the link has none
+	Sticky       bool    // Remember VariantID in a cookie for returning visitors
+
+	// Set for mobile visitors of links with deep linking configured for their platform
+	AppURL      string // URL that opens the app
+	FallbackURL string // Store page (or Destination) used when the app is not installed
+}
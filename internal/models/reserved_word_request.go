@@ -0,0 +1,14 @@
+package models
+
+import "shortly-be/internal/entities"
+
+// ReservedWordRequest represents the request body for reserving a word
+type ReservedWordRequest struct {
+	Word string `json:"word" binding:"required,max=64"`
+}
+
+// ReservedWordsResponse lists the words that can't be used as short codes
+type ReservedWordsResponse struct {
+	Words  []entities.ReservedWord `json:"words"`  // Reserved by admins
+	Routes []string                `json:"routes"` // Top-level route paths, always reserved
+}
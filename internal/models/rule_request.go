@@ -0,0 +1,15 @@
+package models
+
+import "shortly-be/internal/entities"
+
+// RoutingRuleRequest represents the request body for creating or replacing a routing rule
+type RoutingRuleRequest struct {
+	Destination string                  `json:"destination" binding:"required,url"`
+	Position    *int                    `json:"position,omitempty" binding:"omitempty,min=1"` // Optional, new rules are appended by default
+	Conditions  entities.RuleConditions `json:"conditions"`
+}
+
+// ReorderRulesRequest represents the request body for changing the evaluation order of rules
+type ReorderRulesRequest struct {
+	RuleIDs []string `json:"rule_ids" binding:"required"` // Every rule ID of the URL in the new order
+}
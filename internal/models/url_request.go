@@ -4,8 +4,129 @@ import "time"
 
 // CreateURLRequest represents the request body for creating a short URL
 type CreateURLRequest struct {
-	URL       string     `json:"url" binding:"required,url"`        // Gin validation: required and must be valid URL
-	ExpiresAt *time.Time `json:"expires_at,omitempty"`               // Optional expiration date
-	ShortCode *string    `json:"short_code,omitempty"`                // Optional custom short code
+	URL          string     `json:"url" binding:"required,url"`                                        // Gin validation: required and must be valid URL
+	StartsAt     *time.Time `json:"starts_at,omitempty"`                                               // Optional activation date
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`                                              // Optional expiration date
+	ShortCode    *string    `json:"short_code,omitempty"`                                              // Optional custom short code
+	Domain       *string    `json:"domain,omitempty"`                                                  // Optional verified custom domain, defaults to BASE_URL
+	RedirectType *int       `json:"redirect_type,omitempty" binding:"omitempty,oneof=301 302 307 308"` // Optional redirect status, defaults to the user's preference (302 unless changed)
+	Password     *string    `json:"password,omitempty" binding:"omitempty,min=4,max=72"`               // Optional password required before redirecting
+	MaxClicks    *int       `json:"max_clicks,omitempty" binding:"omitempty,min=1"`                    // Optional click limit (1 = one-time link)
+
+	Destinations   []DestinationRequest `json:"destinations,omitempty" binding:"omitempty,dive"` // Optional weighted A/B destinations
+	StickyVariants bool                 `json:"sticky_variants,omitempty"`                       // Keep visitors on the variant they first saw
+	DeepLink       *DeepLinkRequest     `json:"deep_link,omitempty"`                             // Optional mobile app deep link configuration
+
+	ForwardQuery    bool   `json:"forward_query,omitempty"`                                                   // Merge the visitor's query parameters into the destination
+	QueryPrecedence string `json:"query_precedence,omitempty" binding:"omitempty,oneof=destination incoming"` // Conflict resolution, defaults to "destination"
+	ForwardPath     bool   `json:"forward_path,omitempty"`                                                    // Append /:shortCode/*rest path segments to the destination
+
+	Title    *string                `json:"title,omitempty" binding:"omitempty,max=255"`
+	Notes    *string                `json:"notes,omitempty" binding:"omitempty,max=5000"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`                                    // Free-form JSON object
+	FolderID *string                `json:"folder_id,omitempty"`                                   // Folder to file the link in
+	Tags     []string               `json:"tags,omitempty" binding:"omitempty,max=20,dive,max=50"` // Tag names, created if they don't exist yet
+
+	UTM         *UTMParams `json:"utm,omitempty"`           // UTM parameters added to the destination, overriding those of the preset
+	UTMPresetID *string    `json:"utm_preset_id,omitempty"` // Saved UTM preset added to the destination
+
+	ReuseExisting bool `json:"reuse_existing,omitempty"` // Return the user's active plain link to the same destination, if any, instead of creating one
+}
+
+// DeepLinkRequest configures app deep linking for iOS and Android visitors
+type DeepLinkRequest struct {
+	IOSURL          string `json:"ios_url,omitempty"`                               // Custom scheme or universal link, e.g. myapp://promo
+	IOSStoreURL     string `json:"ios_store_url,omitempty" binding:"omitempty,url"` // App Store fallback
+	AndroidURL      string `json:"android_url,omitempty"`                           // Custom scheme, app link or intent:// URL
+	AndroidStoreURL string `json:"android_store_url,omitempty" binding:"omitempty,url"`
+}
+
+// DestinationRequest describes one weighted A/B destination
+type DestinationRequest struct {
+	ID     *string `json:"id,omitempty"` // Existing destination to keep (preserves its click history), omit for new ones
+	URL    string  `json:"url" binding:"required,url"`
+	Weight int     `json:"weight" binding:"required,min=1"`
+	Label  *string `json:"label,omitempty" binding:"omitempty,max=100"`
+}
+
+// UpdateURLRequest represents the request body for PATCH /api/v1/url/:shortCode.
+// Omitted fields are left unchanged; null clears the field where that makes sense.
+type UpdateURLRequest struct {
+	URL          Optional[string] `json:"url"`           // New destination URL (the previous one is kept in the revision history)
+	StartsAt     Optional[string] `json:"starts_at"`     // ISO 8601 string, null or "" activates the link immediately
+	ExpiresAt    Optional[string] `json:"expires_at"`    // ISO 8601 string, null or "" removes the expiration
+	RedirectType Optional[int]    `json:"redirect_type"` // 301, 302, 307 or 308
+	Password     Optional[string] `json:"password"`      // New password, null or "" removes protection
+	MaxClicks    Optional[int]    `json:"max_clicks"`    // New click limit, null removes the limit
+
+	Destinations   Optional[[]DestinationRequest] `json:"destinations"`    // Replaces the A/B destinations, null or [] removes them
+	StickyVariants Optional[bool]                 `json:"sticky_variants"` // Keep visitors on the variant they first saw
+	DeepLink       Optional[DeepLinkRequest]      `json:"deep_link"`       // Replaces the deep link configuration, null removes it
+
+	ForwardQuery    Optional[bool]   `json:"forward_query"`    // Merge the visitor's query parameters into the destination
+	QueryPrecedence Optional[string] `json:"query_precedence"` // "destination" or "incoming", null resets to "destination"
+	ForwardPath     Optional[bool]   `json:"forward_path"`     // Append /:shortCode/*rest path segments to the destination
+
+	Title    Optional[string]                 `json:"title"`     // null or "" removes the title
+	Notes    Optional[string]                 `json:"notes"`     // null or "" removes the notes
+	Metadata Optional[map[string]interface{}] `json:"metadata"`  // Replaces the metadata, null removes it
+	FolderID Optional[string]                 `json:"folder_id"` // null moves the link out of its folder
+	Tags     Optional[[]string]               `json:"tags"`      // Replaces the tags, null or [] removes them
+}
+
+// URLListQuery holds the filters accepted by link listings, aggregate analytics and batch actions,
+// plus the sorting and pagination of listings
+type URLListQuery struct {
+	Archived      bool       `form:"archived" json:"archived"` // List the archive instead of the active links
+	Tag           string     `form:"tag" json:"tag"`           // Tag name
+	Folder        string     `form:"folder" json:"folder"`     // Folder ID, includes subfolders
+	Status        string     `form:"status" json:"status" binding:"omitempty,oneof=active expired"`
+	CreatedAfter  *time.Time `form:"created_after" json:"created_after"`   // RFC 3339, inclusive
+	CreatedBefore *time.Time `form:"created_before" json:"created_before"` // RFC 3339, exclusive
+	Domain        *string    `form:"domain" json:"domain"`                 // Custom domain; an empty value selects the default domain
+	Search        string     `form:"q" json:"q" binding:"max=200"`
+	Campaign      string     `form:"campaign" json:"campaign"` // utm_campaign of the destination
+
+	Sort   string `form:"sort" json:"-" binding:"omitempty,oneof=created clicks expires"` // Defaults to created
+	Order  string `form:"order" json:"-" binding:"omitempty,oneof=asc desc"`              // Defaults to desc
+	Limit  int    `form:"limit" json:"-" binding:"omitempty,min=1,max=200"`               // Defaults to 50
+	Cursor string `form:"cursor" json:"-"`                                                // next_cursor of the previous page
 }
 
+// UTMParams are the UTM parameters of a link's destination; empty values are left out
+type UTMParams struct {
+	Source   string `json:"source,omitempty" binding:"max=255"`
+	Medium   string `json:"medium,omitempty" binding:"max=255"`
+	Campaign string `json:"campaign,omitempty" binding:"max=255"`
+	Term     string `json:"term,omitempty" binding:"max=255"`
+	Content  string `json:"content,omitempty" binding:"max=255"`
+}
+
+// UTMPresetRequest represents the request body for creating a UTM preset
+type UTMPresetRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	UTMParams
+}
+
+// UpdateUTMPresetRequest represents the request body for PATCH /api/v1/utm-presets/:presetId.
+// Omitted fields are unchanged; "" clears a parameter.
+type UpdateUTMPresetRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Source   *string `json:"source" binding:"omitempty,max=255"`
+	Medium   *string `json:"medium" binding:"omitempty,max=255"`
+	Campaign *string `json:"campaign" binding:"omitempty,max=255"`
+	Term     *string `json:"term" binding:"omitempty,max=255"`
+	Content  *string `json:"content" binding:"omitempty,max=255"`
+}
+
+// ExportQuery holds the format and time range of link and click exports
+type ExportQuery struct {
+	Format string     `form:"format" binding:"omitempty,oneof=csv json ndjson"` // Defaults to csv
+	From   *time.Time `form:"from"`                                             // RFC 3339, inclusive (clicks only)
+	To     *time.Time `form:"to"`                                               // RFC 3339, exclusive (clicks only)
+}
+
+// UnlockURLRequest represents the request body for unlocking a password-protected URL
+type UnlockURLRequest struct {
+	Password string `json:"password" form:"password" binding:"required"`
+}
@@ -1,22 +1,73 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"shortly-be/internal/entities"
+)
 
 // CreateURLResponse represents the response after creating a short URL
 type CreateURLResponse struct {
-	ShortCode   string     `json:"short_code"`
-	OriginalURL string     `json:"original_url"`
-	ShortURL    string     `json:"short_url"` // Full short URL (base URL + short code)
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
+	Domain            string     `json:"domain,omitempty"`
+	ShortCode         string     `json:"short_code"`
+	OriginalURL       string     `json:"original_url"`
+	ShortURL          string     `json:"short_url"` // Full short URL (base URL + short code)
+	StartsAt          *time.Time `json:"starts_at,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	RedirectType      int        `json:"redirect_type"`
+	PasswordProtected bool       `json:"password_protected"`
+	MaxClicks         *int       `json:"max_clicks,omitempty"`
+	StickyVariants    bool       `json:"sticky_variants"`
+	ForwardQuery      bool       `json:"forward_query"`
+	QueryPrecedence   string     `json:"query_precedence"`
+	ForwardPath       bool       `json:"forward_path"`
+	CreatedAt         time.Time  `json:"created_at"`
+
+	Destinations []entities.URLDestination `json:"destinations,omitempty"`
+	DeepLink     *entities.DeepLink        `json:"deep_link,omitempty"`
+
+	Title    *string                `json:"title,omitempty"`
+	Notes    *string                `json:"notes,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	FolderID *string                `json:"folder_id,omitempty"`
+	Tags     []entities.Tag         `json:"tags"`
+
+	Reused bool `json:"reused,omitempty"` // The link already existed and was returned because of reuse_existing
 }
 
 // URLStatsResponse represents the response for URL statistics
 type URLStatsResponse struct {
-	ShortCode   string     `json:"short_code"`
-	OriginalURL string     `json:"original_url"`
-	ClickCount  int        `json:"click_count"`
-	CreatedAt   time.Time  `json:"created_at"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Domain            string     `json:"domain,omitempty"`
+	ShortCode         string     `json:"short_code"`
+	OriginalURL       string     `json:"original_url"`
+	ClickCount        int        `json:"click_count"`
+	CreatedAt         time.Time  `json:"created_at"`
+	StartsAt          *time.Time `json:"starts_at,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	RedirectType      int        `json:"redirect_type"`
+	PasswordProtected bool       `json:"password_protected"`
+	MaxClicks         *int       `json:"max_clicks,omitempty"`
+	StickyVariants    bool       `json:"sticky_variants"`
+	ForwardQuery      bool       `json:"forward_query"`
+	QueryPrecedence   string     `json:"query_precedence"`
+	ForwardPath       bool       `json:"forward_path"`
+	ArchivedAt        *time.Time `json:"archived_at,omitempty"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
+	PurgeAt           *time.Time `json:"purge_at,omitempty"` // When a trashed link will be permanently deleted
+
+	Destinations []entities.URLDestination `json:"destinations,omitempty"`
+	DeepLink     *entities.DeepLink        `json:"deep_link,omitempty"`
+
+	Title    *string                `json:"title,omitempty"`
+	Notes    *string                `json:"notes,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	FolderID *string                `json:"folder_id,omitempty"`
+	Tags     []entities.Tag         `json:"tags"`
 }
 
+// URLListResponse is one page of the links of a user
+type URLListResponse struct {
+	URLs       []*URLStatsResponse `json:"urls"`
+	NextCursor *string             `json:"next_cursor"` // Pass as ?cursor= for the next page, null on the last page
+	Total      int                 `json:"total"`       // Links matching the filters across all pages
+}
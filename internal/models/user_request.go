@@ -0,0 +1,9 @@
+package models
+
+// UpdatePreferencesRequest represents the request body for updating user preferences
+type UpdatePreferencesRequest struct {
+	DefaultRedirectType *int               `json:"default_redirect_type,omitempty" binding:"omitempty,oneof=301 302 307 308"`
+	TrackingParams      Optional[[]string] `json:"tracking_params"`           // Parameters stripped from new links ("utm_*" matches a prefix); null restores the default, [] keeps all
+	StripFragments      *bool              `json:"strip_fragments,omitempty"` // Remove #fragments from new links
+	CodeStrategy        Optional[string]   `json:"code_strategy"`             // random, sequence, hashids or words; null restores the default
+}
@@ -0,0 +1,9 @@
+package models
+
+// PreferencesResponse represents the user's link defaults
+type PreferencesResponse struct {
+	DefaultRedirectType int      `json:"default_redirect_type"`
+	TrackingParams      []string `json:"tracking_params"` // null when the deployment's default list applies
+	StripFragments      bool     `json:"strip_fragments"`
+	CodeStrategy        *string  `json:"code_strategy"` // null when the deployment's default strategy applies
+}
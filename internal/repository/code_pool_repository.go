@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// CodePoolRepository defines the interface for the pool of pre-generated short codes
+type CodePoolRepository interface {
+	Lease(n int) ([]string, error)
+	Add(codes []string, size int) (int, error)
+	Count() (int, error)
+}
+
+type codePoolRepository struct {
+	db *sql.DB
+}
+
+// NewCodePoolRepository creates a new code pool repository
+func NewCodePoolRepository(db *sql.DB) CodePoolRepository {
+	return &codePoolRepository{db: db}
+}
+
+// Lease removes up to n codes from the pool and returns them. Concurrent leases skip each other's rows,
+// so every code is handed to a single instance. Codes a link took since they were pooled (e.g. as a
+// custom code) are dropped instead of returned.
+func (r *codePoolRepository) Lease(n int) ([]string, error) {
+	query := `
+		WITH leased AS (
+			DELETE FROM short_code_pool
+			WHERE short_code IN (
+				SELECT short_code FROM short_code_pool
+				ORDER BY created_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING short_code
+		)
+		SELECT l.short_code FROM leased l
+		WHERE NOT EXISTS (SELECT 1 FROM urls u WHERE u.domain = '' AND u.short_code = l.short_code)
+	`
+
+	rows, err := r.db.Query(query, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lease short codes: %w", err)
+	}
+	defer rows.Close()
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, fmt.Errorf("failed to scan leased short code: %w", err)
+		}
+		codes = append(codes, code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lease short codes: %w", err)
+	}
+	return codes, nil
+}
+
+// codePoolLockKey is the advisory lock serializing additions to the pool across instances
+const codePoolLockKey = 7_210_486_522
+
+// Add puts codes into the pool until it holds size codes, leaving out those already pooled or used by a link
+// on the default domain. Instances refilling at the same time take turns, so together they never fill the pool
+// beyond size. It returns the number of codes added.
+func (r *codePoolRepository) Add(codes []string, size int) (int, error) {
+	if len(codes) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, codePoolLockKey); err != nil {
+		return 0, fmt.Errorf("failed to lock short code pool: %w", err)
+	}
+
+	query := `
+		INSERT INTO short_code_pool (short_code)
+		SELECT c FROM (SELECT DISTINCT c FROM unnest($1::text[]) AS c) AS candidates
+		WHERE NOT EXISTS (SELECT 1 FROM urls u WHERE u.domain = '' AND u.short_code = c)
+			AND NOT EXISTS (SELECT 1 FROM short_code_pool p WHERE p.short_code = c)
+		LIMIT GREATEST($2 - (SELECT COUNT(*) FROM short_code_pool), 0)
+		ON CONFLICT (short_code) DO NOTHING
+	`
+
+	result, err := tx.Exec(query, pq.Array(codes), size)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add short codes to pool: %w", err)
+	}
+	added, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to add short codes to pool: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit pooled short codes: %w", err)
+	}
+	return int(added), nil
+}
+
+// Count returns the number of unused codes in the pool
+func (r *codePoolRepository) Count() (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM short_code_pool`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count pooled short codes: %w", err)
+	}
+	return count, nil
+}
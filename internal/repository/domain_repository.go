@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"shortly-be/internal/entities"
+)
+
+// ErrDomainNotFound is returned when a domain does not exist (or belongs to another user)
+var ErrDomainNotFound = errors.New("domain not found")
+
+// DomainRepository defines the interface for custom domain database operations
+type DomainRepository interface {
+	Create(domain *entities.Domain) (*entities.Domain, error)
+	ListByUserID(userID string) ([]entities.Domain, error)
+	FindByHostname(hostname string) (*entities.Domain, error)
+	FindByUserHostname(userID string, hostname string) (*entities.Domain, error)
+	MarkVerified(domainID string) (*entities.Domain, error)
+	Delete(hostname string, userID string) error
+}
+
+type domainRepository struct {
+	db *sql.DB
+}
+
+// NewDomainRepository creates a new domain repository
+func NewDomainRepository(db *sql.DB) DomainRepository {
+	return &domainRepository{db: db}
+}
+
+// domainColumns is the column list scanned by scanDomain
+const domainColumns = `id, user_id, hostname, verification_token, verified_at, created_at`
+
+// scanDomain scans a single domain row selected with domainColumns
+func scanDomain(row rowScanner) (*entities.Domain, error) {
+	var domain entities.Domain
+	err := row.Scan(
+		&domain.ID,
+		&domain.UserID,
+		&domain.Hostname,
+		&domain.VerificationToken,
+		&domain.VerifiedAt,
+		&domain.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+// Create inserts a new, unverified claim on a domain. Several users may claim a hostname until one of
+// them verifies it, so an unverified claim can't lock the real owner out.
+func (r *domainRepository) Create(domain *entities.Domain) (*entities.Domain, error) {
+	query := `
+		INSERT INTO domains (user_id, hostname, verification_token)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (SELECT 1 FROM domains WHERE hostname = $2 AND verified_at IS NOT NULL)
+		RETURNING ` + domainColumns
+
+	created, err := scanDomain(r.db.QueryRow(query, domain.UserID, domain.Hostname, domain.VerificationToken))
+	if err == sql.ErrNoRows || isUniqueViolation(err) {
+		return nil, fmt.Errorf("domain '%s' is already registered", domain.Hostname)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create domain: %w", err)
+	}
+
+	return created, nil
+}
+
+// ListByUserID retrieves the domains of a user
+func (r *domainRepository) ListByUserID(userID string) ([]entities.Domain, error) {
+	query := `
+		SELECT ` + domainColumns + `
+		FROM domains
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domains: %w", err)
+	}
+	defer rows.Close()
+
+	domains := []entities.Domain{}
+	for rows.Next() {
+		domain, err := scanDomain(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan domain: %w", err)
+		}
+		domains = append(domains, *domain)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating domains: %w", err)
+	}
+
+	return domains, nil
+}
+
+// FindByHostname retrieves the verified domain with a hostname; unverified claims are not returned
+func (r *domainRepository) FindByHostname(hostname string) (*entities.Domain, error) {
+	query := `SELECT ` + domainColumns + ` FROM domains WHERE hostname = $1 AND verified_at IS NOT NULL`
+
+	domain, err := scanDomain(r.db.QueryRow(query, hostname))
+	if err == sql.ErrNoRows {
+		return nil, ErrDomainNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find domain: %w", err)
+	}
+
+	return domain, nil
+}
+
+// FindByUserHostname retrieves a user's claim on a hostname, verified or not
+func (r *domainRepository) FindByUserHostname(userID string, hostname string) (*entities.Domain, error) {
+	query := `SELECT ` + domainColumns + ` FROM domains WHERE user_id = $1 AND hostname = $2`
+
+	domain, err := scanDomain(r.db.QueryRow(query, userID, hostname))
+	if err == sql.ErrNoRows {
+		return nil, ErrDomainNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find domain: %w", err)
+	}
+
+	return domain, nil
+}
+
+// MarkVerified records that DNS ownership of a domain was confirmed and removes the other users'
+// unverified claims on its hostname
+func (r *domainRepository) MarkVerified(domainID string) (*entities.Domain, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE domains
+		SET verified_at = COALESCE(verified_at, NOW())
+		WHERE id = $1
+		RETURNING ` + domainColumns
+
+	domain, err := scanDomain(tx.QueryRow(query, domainID))
+	if err == sql.ErrNoRows {
+		return nil, ErrDomainNotFound
+	}
+	// Another user verified the hostname first
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("domain is already verified by another account")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify domain: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM domains WHERE hostname = $1 AND id <> $2`, domain.Hostname, domain.ID); err != nil {
+		return nil, fmt.Errorf("failed to remove competing domain claims: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit domain verification: %w", err)
+	}
+
+	return domain, nil
+}
+
+// Delete removes a domain owned by the user. Verified domains that still have links (including trashed ones)
+// can't be removed; unverified claims never have links.
+func (r *domainRepository) Delete(hostname string, userID string) error {
+	var verified bool
+	err := r.db.QueryRow(`SELECT verified_at IS NOT NULL FROM domains WHERE hostname = $1 AND user_id = $2`, hostname, userID).Scan(&verified)
+	if err == sql.ErrNoRows {
+		return ErrDomainNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find domain: %w", err)
+	}
+
+	if verified {
+		var linkCount int
+		if err := r.db.QueryRow(`SELECT COUNT(*) FROM urls WHERE domain = $1`, hostname).Scan(&linkCount); err != nil {
+			return fmt.Errorf("failed to count domain links: %w", err)
+		}
+		if linkCount > 0 {
+			return fmt.Errorf("domain '%s' still has %d links", hostname, linkCount)
+		}
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM domains WHERE hostname = $1 AND user_id = $2`, hostname, userID); err != nil {
+		return fmt.Errorf("failed to delete domain: %w", err)
+	}
+
+	return nil
+}
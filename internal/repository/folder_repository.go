@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"shortly-be/internal/entities"
+)
+
+// ErrFolderNotFound is returned when a folder does not exist (or belongs to another user)
+var ErrFolderNotFound = errors.New("folder not found")
+
+// FolderRepository defines the interface for folder database operations
+type FolderRepository interface {
+	ListByUserID(userID string) ([]entities.Folder, error)
+	FindByID(userID, folderID string) (*entities.Folder, error)
+	Create(folder *entities.Folder) (*entities.Folder, error)
+	Update(folder *entities.Folder) (*entities.Folder, error)
+	Delete(userID, folderID string) error
+}
+
+type folderRepository struct {
+	db *sql.DB
+}
+
+// NewFolderRepository creates a new folder repository
+func NewFolderRepository(db *sql.DB) FolderRepository {
+	return &folderRepository{db: db}
+}
+
+// folderColumns is the column list scanned by scanFolder
+const folderColumns = `id, user_id, parent_id, name, created_at`
+
+// scanFolder scans a single folder row selected with folderColumns
+func scanFolder(row rowScanner) (*entities.Folder, error) {
+	var folder entities.Folder
+	err := row.Scan(
+		&folder.ID,
+		&folder.UserID,
+		&folder.ParentID,
+		&folder.Name,
+		&folder.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// ListByUserID retrieves all folders of a user; the hierarchy is expressed through parent_id
+func (r *folderRepository) ListByUserID(userID string) ([]entities.Folder, error) {
+	query := `
+		SELECT ` + folderColumns + `
+		FROM folders
+		WHERE user_id = $1
+		ORDER BY LOWER(name) ASC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folders: %w", err)
+	}
+	defer rows.Close()
+
+	folders := []entities.Folder{}
+	for rows.Next() {
+		folder, err := scanFolder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan folder: %w", err)
+		}
+		folders = append(folders, *folder)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating folders: %w", err)
+	}
+
+	return folders, nil
+}
+
+// FindByID retrieves a folder owned by the user
+func (r *folderRepository) FindByID(userID, folderID string) (*entities.Folder, error) {
+	query := `SELECT ` + folderColumns + ` FROM folders WHERE id = $1 AND user_id = $2`
+
+	folder, err := scanFolder(r.db.QueryRow(query, folderID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrFolderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder: %w", err)
+	}
+
+	return folder, nil
+}
+
+// Create inserts a new folder
+func (r *folderRepository) Create(folder *entities.Folder) (*entities.Folder, error) {
+	query := `
+		INSERT INTO folders (user_id, parent_id, name)
+		VALUES ($1, $2, $3)
+		RETURNING ` + folderColumns
+
+	created, err := scanFolder(r.db.QueryRow(query, folder.UserID, folder.ParentID, folder.Name))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("folder '%s' already exists", folder.Name)
+		}
+		return nil, fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	return created, nil
+}
+
+// Update renames or moves a folder owned by the user.
+// Moving a folder below itself or one of its subfolders is rejected.
+func (r *folderRepository) Update(folder *entities.Folder) (*entities.Folder, error) {
+	if folder.ParentID != nil {
+		var cycle bool
+		err := r.db.QueryRow(`
+			WITH RECURSIVE subfolders AS (
+				SELECT id FROM folders WHERE id = $1
+				UNION ALL
+				SELECT f.id FROM folders f JOIN subfolders s ON f.parent_id = s.id
+			)
+			SELECT EXISTS (SELECT 1 FROM subfolders WHERE id = $2)
+		`, folder.ID, *folder.ParentID).Scan(&cycle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check folder hierarchy: %w", err)
+		}
+		if cycle {
+			return nil, fmt.Errorf("a folder can't be moved into itself or one of its subfolders")
+		}
+	}
+
+	query := `
+		UPDATE folders
+		SET name = $1, parent_id = $2
+		WHERE id = $3 AND user_id = $4
+		RETURNING ` + folderColumns
+
+	updated, err := scanFolder(r.db.QueryRow(query, folder.Name, folder.ParentID, folder.ID, folder.UserID))
+	if err == sql.ErrNoRows {
+		return nil, ErrFolderNotFound
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("folder '%s' already exists", folder.Name)
+		}
+		return nil, fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes a folder owned by the user along with its subfolders; their links are unfiled
+func (r *folderRepository) Delete(userID, folderID string) error {
+	result, err := r.db.Exec(`DELETE FROM folders WHERE id = $1 AND user_id = $2`, folderID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete folder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrFolderNotFound
+	}
+
+	return nil
+}
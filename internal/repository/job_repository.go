@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"shortly-be/internal/entities"
+)
+
+// ErrJobNotFound is returned when a job does not exist (or belongs to another user)
+var ErrJobNotFound = errors.New("job not found")
+
+// JobRepository defines the interface for background job database operations
+type JobRepository interface {
+	Create(job *entities.Job) (*entities.Job, error)
+	FindByID(userID, jobID string) (*entities.Job, error)
+	UpdateProgress(jobID string, status string, processed, failed int) error
+	Finish(jobID string, status string, result interface{}, errMsg *string) error
+	Heartbeat(jobID string) error
+	FailStale(staleBefore time.Time, errMsg string) (int, error)
+}
+
+type jobRepository struct {
+	db *sql.DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *sql.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+// jobColumns is the column list scanned by scanJob
+const jobColumns = `id, user_id, kind, status, total, processed, failed, result, error, created_at, finished_at`
+
+// scanJob scans a single job row selected with jobColumns
+func scanJob(row rowScanner) (*entities.Job, error) {
+	var job entities.Job
+	var result []byte
+	err := row.Scan(
+		&job.ID,
+		&job.UserID,
+		&job.Kind,
+		&job.Status,
+		&job.Total,
+		&job.Processed,
+		&job.Failed,
+		&result,
+		&job.Error,
+		&job.CreatedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) > 0 {
+		job.Result = json.RawMessage(result)
+	}
+	return &job, nil
+}
+
+// Create inserts a new pending job
+func (r *jobRepository) Create(job *entities.Job) (*entities.Job, error) {
+	query := `
+		INSERT INTO jobs (user_id, kind, status, total)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + jobColumns
+
+	created, err := scanJob(r.db.QueryRow(query, job.UserID, job.Kind, entities.JobStatusPending, job.Total))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return created, nil
+}
+
+// FindByID retrieves a job owned by the user
+func (r *jobRepository) FindByID(userID, jobID string) (*entities.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1 AND user_id = $2`
+
+	job, err := scanJob(r.db.QueryRow(query, jobID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// UpdateProgress records the status and progress of a running job
+func (r *jobRepository) UpdateProgress(jobID string, status string, processed, failed int) error {
+	_, err := r.db.Exec(`
+		UPDATE jobs SET status = $1, processed = $2, failed = $3, heartbeat_at = NOW() WHERE id = $4
+	`, status, processed, failed, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to update job progress: %w", err)
+	}
+	return nil
+}
+
+// Finish marks a job completed or failed, storing its JSON-encoded result or error
+func (r *jobRepository) Finish(jobID string, status string, result interface{}, errMsg *string) error {
+	var resultJSON interface{}
+	if result != nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode job result: %w", err)
+		}
+		resultJSON = encoded
+	}
+
+	_, err := r.db.Exec(`
+		UPDATE jobs
+		SET status = $1, result = $2, error = $3, finished_at = NOW()
+		WHERE id = $4
+	`, status, resultJSON, errMsg, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to finish job: %w", err)
+	}
+	return nil
+}
+
+// Heartbeat records that the process running a job is still alive
+func (r *jobRepository) Heartbeat(jobID string) error {
+	if _, err := r.db.Exec(`UPDATE jobs SET heartbeat_at = NOW() WHERE id = $1`, jobID); err != nil {
+		return fmt.Errorf("failed to record job heartbeat: %w", err)
+	}
+	return nil
+}
+
+// FailStale marks pending and running jobs without a heartbeat since staleBefore as failed and returns their number
+func (r *jobRepository) FailStale(staleBefore time.Time, errMsg string) (int, error) {
+	result, err := r.db.Exec(`
+		UPDATE jobs
+		SET status = $1, error = $2, finished_at = NOW()
+		WHERE status IN ($3, $4) AND heartbeat_at < $5
+	`, entities.JobStatusFailed, errMsg, entities.JobStatusPending, entities.JobStatusRunning, staleBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail stale jobs: %w", err)
+	}
+
+	failed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(failed), nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"shortly-be/internal/entities"
+)
+
+// ErrReservedWordNotFound is returned when a word is not reserved
+var ErrReservedWordNotFound = errors.New("reserved word not found")
+
+// ReservedWordRepository defines the interface for reserved word database operations
+type ReservedWordRepository interface {
+	List() ([]entities.ReservedWord, error)
+	Create(word *entities.ReservedWord) (*entities.ReservedWord, error)
+	Delete(word string) error
+}
+
+type reservedWordRepository struct {
+	db *sql.DB
+}
+
+// NewReservedWordRepository creates a new reserved word repository
+func NewReservedWordRepository(db *sql.DB) ReservedWordRepository {
+	return &reservedWordRepository{db: db}
+}
+
+// reservedWordColumns is the column list scanned by scanReservedWord
+const reservedWordColumns = `word, created_by, created_at`
+
+// scanReservedWord scans a single reserved word row selected with reservedWordColumns
+func scanReservedWord(row rowScanner) (*entities.ReservedWord, error) {
+	var word entities.ReservedWord
+	if err := row.Scan(&word.Word, &word.CreatedBy, &word.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &word, nil
+}
+
+// List retrieves all reserved words in alphabetical order
+func (r *reservedWordRepository) List() ([]entities.ReservedWord, error) {
+	rows, err := r.db.Query(`SELECT ` + reservedWordColumns + ` FROM reserved_words ORDER BY word ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserved words: %w", err)
+	}
+	defer rows.Close()
+
+	words := []entities.ReservedWord{}
+	for rows.Next() {
+		word, err := scanReservedWord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reserved word: %w", err)
+		}
+		words = append(words, *word)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reserved words: %w", err)
+	}
+
+	return words, nil
+}
+
+// Create reserves a word
+func (r *reservedWordRepository) Create(word *entities.ReservedWord) (*entities.ReservedWord, error) {
+	query := `
+		INSERT INTO reserved_words (word, created_by)
+		VALUES ($1, $2)
+		RETURNING ` + reservedWordColumns
+
+	created, err := scanReservedWord(r.db.QueryRow(query, word.Word, word.CreatedBy))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("'%s' is already reserved", word.Word)
+		}
+		return nil, fmt.Errorf("failed to reserve word: %w", err)
+	}
+
+	return created, nil
+}
+
+// Delete releases a reserved word
+func (r *reservedWordRepository) Delete(word string) error {
+	result, err := r.db.Exec(`DELETE FROM reserved_words WHERE word = $1`, word)
+	if err != nil {
+		return fmt.Errorf("failed to delete reserved word: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrReservedWordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"shortly-be/internal/entities"
+)
+
+// RoutingRuleRepository defines the interface for routing rule database operations
+type RoutingRuleRepository interface {
+	ListByURLID(urlID string) ([]entities.RoutingRule, error)
+	Create(rule *entities.RoutingRule) (*entities.RoutingRule, error)
+	Update(rule *entities.RoutingRule) error
+	Delete(urlID, ruleID string) error
+	Reorder(urlID string, ruleIDs []string) error
+}
+
+type routingRuleRepository struct {
+	db *sql.DB
+}
+
+// NewRoutingRuleRepository creates a new routing rule repository
+func NewRoutingRuleRepository(db *sql.DB) RoutingRuleRepository {
+	return &routingRuleRepository{db: db}
+}
+
+// routingRuleColumns is the column list scanned by scanRoutingRule
+const routingRuleColumns = `id, url_id, position, destination, conditions, created_at`
+
+// scanRoutingRule scans a single rule row selected with routingRuleColumns
+func scanRoutingRule(row rowScanner) (*entities.RoutingRule, error) {
+	var rule entities.RoutingRule
+	var conditions []byte
+	err := row.Scan(
+		&rule.ID,
+		&rule.URLID,
+		&rule.Position,
+		&rule.Destination,
+		&conditions,
+		&rule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+		return nil, fmt.Errorf("failed to decode rule conditions: %w", err)
+	}
+	return &rule, nil
+}
+
+// ListByURLID retrieves the rules of a URL in evaluation order
+func (r *routingRuleRepository) ListByURLID(urlID string) ([]entities.RoutingRule, error) {
+	query := `
+		SELECT ` + routingRuleColumns + `
+		FROM url_routing_rules
+		WHERE url_id = $1
+		ORDER BY position ASC, created_at ASC
+	`
+
+	rows, err := r.db.Query(query, urlID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get routing rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []entities.RoutingRule{}
+	for rows.Next() {
+		rule, err := scanRoutingRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan routing rule: %w", err)
+		}
+		rules = append(rules, *rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating routing rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Create inserts a new rule; a zero position appends it after the existing rules
+func (r *routingRuleRepository) Create(rule *entities.RoutingRule) (*entities.RoutingRule, error) {
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rule conditions: %w", err)
+	}
+
+	query := `
+		INSERT INTO url_routing_rules (url_id, position, destination, conditions)
+		VALUES (
+			$1,
+			CASE WHEN $2 > 0 THEN $2 ELSE (SELECT COALESCE(MAX(position), 0) + 1 FROM url_routing_rules WHERE url_id = $1) END,
+			$3,
+			$4
+		)
+		RETURNING ` + routingRuleColumns
+
+	created, err := scanRoutingRule(r.db.QueryRow(query, rule.URLID, rule.Position, rule.Destination, conditions))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create routing rule: %w", err)
+	}
+
+	return created, nil
+}
+
+// Update replaces the destination, conditions and position of a rule
+func (r *routingRuleRepository) Update(rule *entities.RoutingRule) error {
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return fmt.Errorf("failed to encode rule conditions: %w", err)
+	}
+
+	query := `
+		UPDATE url_routing_rules
+		SET position = $1, destination = $2, conditions = $3
+		WHERE id = $4 AND url_id = $5
+	`
+
+	result, err := r.db.Exec(query, rule.Position, rule.Destination, conditions, rule.ID, rule.URLID)
+	if err != nil {
+		return fmt.Errorf("failed to update routing rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("routing rule not found")
+	}
+
+	return nil
+}
+
+// Delete removes a rule from a URL
+func (r *routingRuleRepository) Delete(urlID, ruleID string) error {
+	result, err := r.db.Exec(`DELETE FROM url_routing_rules WHERE id = $1 AND url_id = $2`, ruleID, urlID)
+	if err != nil {
+		return fmt.Errorf("failed to delete routing rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("routing rule not found")
+	}
+
+	return nil
+}
+
+// Reorder assigns positions 1..n following the order of ruleIDs, which must list every rule of the URL
+func (r *routingRuleRepository) Reorder(urlID string, ruleIDs []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM url_routing_rules WHERE url_id = $1`, urlID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count routing rules: %w", err)
+	}
+	if count != len(ruleIDs) {
+		return fmt.Errorf("rule order must list all %d rules of the URL", count)
+	}
+
+	for i, ruleID := range ruleIDs {
+		result, err := tx.Exec(`UPDATE url_routing_rules SET position = $1 WHERE id = $2 AND url_id = $3`, i+1, ruleID, urlID)
+		if err != nil {
+			return fmt.Errorf("failed to reorder routing rules: %w", err)
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+			return fmt.Errorf("routing rule not found")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rule order: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"shortly-be/internal/entities"
+)
+
+// ErrTagNotFound is returned when a tag does not exist (or belongs to another user)
+var ErrTagNotFound = errors.New("tag not found")
+
+// TagRepository defines the interface for tag database operations
+type TagRepository interface {
+	ListByUserID(userID string) ([]entities.Tag, error)
+	FindByID(userID, tagID string) (*entities.Tag, error)
+	Create(tag *entities.Tag) (*entities.Tag, error)
+	Update(tag *entities.Tag) (*entities.Tag, error)
+	Delete(userID, tagID string) error
+	ListByURLIDs(urlIDs []string) (map[string][]entities.Tag, error)
+	SetURLTags(urlID, userID string, names []string) ([]entities.Tag, error)
+	AddURLTag(urlID, userID, name string) error
+	RemoveURLTag(urlID, userID, name string) error
+}
+
+type tagRepository struct {
+	db *sql.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *sql.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// tagColumns is the column list scanned by scanTag
+const tagColumns = `id, user_id, name, color, created_at`
+
+// scanTag scans a single tag row selected with tagColumns
+func scanTag(row rowScanner) (*entities.Tag, error) {
+	var tag entities.Tag
+	err := row.Scan(
+		&tag.ID,
+		&tag.UserID,
+		&tag.Name,
+		&tag.Color,
+		&tag.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// ListByUserID retrieves the tags of a user in name order
+func (r *tagRepository) ListByUserID(userID string) ([]entities.Tag, error) {
+	query := `
+		SELECT ` + tagColumns + `
+		FROM tags
+		WHERE user_id = $1
+		ORDER BY LOWER(name) ASC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []entities.Tag{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, *tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Create inserts a new tag
+func (r *tagRepository) Create(tag *entities.Tag) (*entities.Tag, error) {
+	query := `
+		INSERT INTO tags (user_id, name, color)
+		VALUES ($1, $2, $3)
+		RETURNING ` + tagColumns
+
+	created, err := scanTag(r.db.QueryRow(query, tag.UserID, tag.Name, tag.Color))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("tag '%s' already exists", tag.Name)
+		}
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return created, nil
+}
+
+// FindByID retrieves a tag owned by the user
+func (r *tagRepository) FindByID(userID, tagID string) (*entities.Tag, error) {
+	query := `SELECT ` + tagColumns + ` FROM tags WHERE id = $1 AND user_id = $2`
+
+	tag, err := scanTag(r.db.QueryRow(query, tagID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// Update renames or recolors a tag owned by the user
+func (r *tagRepository) Update(tag *entities.Tag) (*entities.Tag, error) {
+	query := `
+		UPDATE tags
+		SET name = $1, color = $2
+		WHERE id = $3 AND user_id = $4
+		RETURNING ` + tagColumns
+
+	updated, err := scanTag(r.db.QueryRow(query, tag.Name, tag.Color, tag.ID, tag.UserID))
+	if err == sql.ErrNoRows {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("tag '%s' already exists", tag.Name)
+		}
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes a tag owned by the user from every link
+func (r *tagRepository) Delete(userID, tagID string) error {
+	result, err := r.db.Exec(`DELETE FROM tags WHERE id = $1 AND user_id = $2`, tagID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrTagNotFound
+	}
+
+	return nil
+}
+
+// ListByURLIDs retrieves the tags of several links at once, keyed by URL ID
+func (r *tagRepository) ListByURLIDs(urlIDs []string) (map[string][]entities.Tag, error) {
+	tagsByURL := make(map[string][]entities.Tag, len(urlIDs))
+	if len(urlIDs) == 0 {
+		return tagsByURL, nil
+	}
+
+	query := `
+		SELECT ut.url_id, t.id, t.user_id, t.name, t.color, t.created_at
+		FROM url_tags ut
+		JOIN tags t ON t.id = ut.tag_id
+		WHERE ut.url_id = ANY($1::uuid[])
+		ORDER BY LOWER(t.name) ASC
+	`
+
+	rows, err := r.db.Query(query, pq.Array(urlIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var urlID string
+		var tag entities.Tag
+		if err := rows.Scan(&urlID, &tag.ID, &tag.UserID, &tag.Name, &tag.Color, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan link tag: %w", err)
+		}
+		tagsByURL[urlID] = append(tagsByURL[urlID], tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating link tags: %w", err)
+	}
+
+	return tagsByURL, nil
+}
+
+// SetURLTags replaces the tags of a link with the given names, creating tags the user doesn't have yet
+func (r *tagRepository) SetURLTags(urlID, userID string, names []string) ([]entities.Tag, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM url_tags WHERE url_id = $1`, urlID); err != nil {
+		return nil, fmt.Errorf("failed to clear link tags: %w", err)
+	}
+
+	tags := make([]entities.Tag, 0, len(names))
+	for _, name := range names {
+		// Reuse an existing tag regardless of case; the no-op update makes RETURNING yield the existing row
+		tag, err := scanTag(tx.QueryRow(`
+			INSERT INTO tags (user_id, name)
+			VALUES ($1, $2)
+			ON CONFLICT (user_id, LOWER(name)) DO UPDATE SET name = tags.name
+			RETURNING `+tagColumns,
+			userID, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save tag: %w", err)
+		}
+
+		if _, err := tx.Exec(`INSERT INTO url_tags (url_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, urlID, tag.ID); err != nil {
+			return nil, fmt.Errorf("failed to tag link: %w", err)
+		}
+		tags = append(tags, *tag)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit link tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// AddURLTag attaches a tag to a link, creating the tag if the user doesn't have it yet
+func (r *tagRepository) AddURLTag(urlID, userID, name string) error {
+	_, err := r.db.Exec(`
+		WITH tag AS (
+			INSERT INTO tags (user_id, name)
+			VALUES ($2, $3)
+			ON CONFLICT (user_id, LOWER(name)) DO UPDATE SET name = tags.name
+			RETURNING id
+		)
+		INSERT INTO url_tags (url_id, tag_id)
+		SELECT $1, id FROM tag
+		ON CONFLICT DO NOTHING
+	`, urlID, userID, name)
+	if err != nil {
+		return fmt.Errorf("failed to tag link: %w", err)
+	}
+	return nil
+}
+
+// RemoveURLTag detaches a tag (matched case-insensitively) from a link; the tag itself is kept
+func (r *tagRepository) RemoveURLTag(urlID, userID, name string) error {
+	_, err := r.db.Exec(`
+		DELETE FROM url_tags ut
+		USING tags t
+		WHERE ut.tag_id = t.id AND ut.url_id = $1 AND t.user_id = $2 AND LOWER(t.name) = LOWER($3)
+	`, urlID, userID, name)
+	if err != nil {
+		return fmt.Errorf("failed to untag link: %w", err)
+	}
+	return nil
+}
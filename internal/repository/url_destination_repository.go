@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"shortly-be/internal/entities"
+)
+
+// DestinationRepository defines the interface for A/B destination database operations
+type DestinationRepository interface {
+	ListByURLID(urlID string) ([]entities.URLDestination, error)
+	Replace(urlID string, destinations []entities.URLDestination) ([]entities.URLDestination, error)
+}
+
+type destinationRepository struct {
+	db *sql.DB
+}
+
+// NewDestinationRepository creates a new destination repository
+func NewDestinationRepository(db *sql.DB) DestinationRepository {
+	return &destinationRepository{db: db}
+}
+
+// destinationColumns is the column list scanned by scanDestination
+const destinationColumns = `id, url_id, position, url, weight, label, created_at`
+
+// scanDestination scans a single destination row selected with destinationColumns
+func scanDestination(row rowScanner) (*entities.URLDestination, error) {
+	var destination entities.URLDestination
+	err := row.Scan(
+		&destination.ID,
+		&destination.URLID,
+		&destination.Position,
+		&destination.URL,
+		&destination.Weight,
+		&destination.Label,
+		&destination.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &destination, nil
+}
+
+// ListByURLID retrieves the destinations of a URL in position order
+func (r *destinationRepository) ListByURLID(urlID string) ([]entities.URLDestination, error) {
+	query := `
+		SELECT ` + destinationColumns + `
+		FROM url_destinations
+		WHERE url_id = $1
+		ORDER BY position ASC
+	`
+
+	rows, err := r.db.Query(query, urlID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get destinations: %w", err)
+	}
+	defer rows.Close()
+
+	destinations := []entities.URLDestination{}
+	for rows.Next() {
+		destination, err := scanDestination(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan destination: %w", err)
+		}
+		destinations = append(destinations, *destination)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating destinations: %w", err)
+	}
+
+	return destinations, nil
+}
+
+// Replace makes the given list the full destination set of a URL.
+// Entries with an ID update the existing row so its click history is kept; the rest are inserted
+// and destinations missing from the list are deleted.
+func (r *destinationRepository) Replace(urlID string, destinations []entities.URLDestination) ([]entities.URLDestination, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	saved, err := replaceDestinations(tx, urlID, destinations)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit destinations: %w", err)
+	}
+
+	return saved, nil
+}
+
+// replaceDestinations runs Replace inside a transaction owned by the caller
+func replaceDestinations(tx *sql.Tx, urlID string, destinations []entities.URLDestination) ([]entities.URLDestination, error) {
+	keep := []string{}
+	for _, destination := range destinations {
+		if destination.ID != "" {
+			keep = append(keep, destination.ID)
+		}
+	}
+
+	// Remove destinations that are no longer listed (their clicks keep a NULL variant)
+	if _, err := tx.Exec(`
+		DELETE FROM url_destinations
+		WHERE url_id = $1 AND id <> ALL($2::uuid[])
+	`, urlID, pq.Array(keep)); err != nil {
+		return nil, fmt.Errorf("failed to remove destinations: %w", err)
+	}
+
+	saved := make([]entities.URLDestination, 0, len(destinations))
+	for i, destination := range destinations {
+		var row *sql.Row
+		if destination.ID != "" {
+			row = tx.QueryRow(`
+				UPDATE url_destinations
+				SET position = $1, url = $2, weight = $3, label = $4
+				WHERE id = $5 AND url_id = $6
+				RETURNING `+destinationColumns,
+				i+1, destination.URL, destination.Weight, destination.Label, destination.ID, urlID)
+		} else {
+			row = tx.QueryRow(`
+				INSERT INTO url_destinations (url_id, position, url, weight, label)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING `+destinationColumns,
+				urlID, i+1, destination.URL, destination.Weight, destination.Label)
+		}
+
+		result, err := scanDestination(row)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("destination '%s' not found", destination.ID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save destination: %w", err)
+		}
+		saved = append(saved, *result)
+	}
+
+	return saved, nil
+}
@@ -1,24 +1,208 @@
 package repository
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	neturl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
+	"shortly-be/internal/codefilter"
 	"shortly-be/internal/entities"
+
+	"github.com/lib/pq"
 )
 
+// ErrURLNotFound is returned when a short code does not resolve to an active URL
+var ErrURLNotFound = errors.New("URL not found or expired")
+
+// ErrURLNotYetActive is returned when a short code exists but its starts_at is in the future
+var ErrURLNotYetActive = errors.New("URL is not active yet")
+
+// ErrClickLimitReached is returned when a URL has used up its max_clicks (or no longer exists)
+var ErrClickLimitReached = errors.New("URL not found or click limit reached")
+
+// ErrShortCodeTaken is returned when another link on the domain already owns the short code or one that reads the same
+var ErrShortCodeTaken = errors.New("short code is already taken")
+
 // URLRepository defines the interface for URL database operations
 type URLRepository interface {
-	Create(shortCode, originalURL string, userID *string, expiresAt *time.Time) (*entities.URL, error)
-	FindByShortCode(shortCode string) (*entities.URL, error)
-	IncrementClickCount(shortCode string) error
-	Delete(shortCode string, userID *string) error
-	UpdateExpiresAt(shortCode string, userID *string, expiresAt *time.Time) error
-	GetStats(shortCode string, userID *string) (*entities.URL, error)
-	GetByUserID(userID string) ([]*entities.URL, error)
-	GetClickAnalytics(urlID string, hours int) ([]map[string]interface{}, error)
+	Create(url *entities.URL) (*entities.URL, error)
+	CreateBatch(urls []*entities.URL) ([]*entities.URL, []error)
+	FindByShortCode(domain, shortCode string) (*entities.URL, error)
+	FindReusableByDestination(userID, domain, originalURL string, redirectType int) (*entities.URL, error)
+	ListWithoutDestinationHash(limit int) ([]*entities.URL, error)
+	SetDestinationHash(urlID, destination string) error
+	ListWithoutCodeSkeleton(afterID string, limit int) ([]*entities.URL, error)
+	SetCodeSkeleton(urlID, shortCode string) error
+	NextCodeSequence() (int64, error)
+	IncrementClickCount(domain, shortCode string, variantID *string) error
+	Delete(domain, shortCode string, userID *string) error
+	Restore(domain, shortCode string, userID string, deletedAfter time.Time) (*entities.URL, error)
+	Purge(domain, shortCode string, userID string) error
+	PurgeDeleted(deletedBefore time.Time) ([]URLKey, error)
+	GetTrashByUserID(userID string) ([]*entities.URL, error)
+	SetArchived(domain, shortCode string, userID string, archived bool) error
+	TransferOwner(domain, shortCode string, fromUserID, toUserID string) error
+	Update(url *entities.URL, changedBy *string, destinations *[]entities.URLDestination) error
+	GetStats(domain, shortCode string, userID *string) (*entities.URL, error)
+	GetByUserID(userID string, filter URLFilter, page URLPage) ([]*entities.URL, int, error)
+	GetClickAnalytics(urlID string, hours int, byVariant bool) ([]map[string]interface{}, error)
+	GetVariantClickTotals(urlID string, hours int) ([]map[string]interface{}, error)
+	GetUserClickAnalytics(userID string, filter URLFilter, hours int) ([]map[string]interface{}, error)
+	GetCampaignAnalytics(userID string, filter URLFilter, hours int) ([]map[string]interface{}, error)
+	StreamByUserID(userID string, filter URLFilter, fn func(url *entities.URL) error) error
+	StreamClicks(urlID string, from, to *time.Time, fn func(click *entities.URLClick) error) error
+}
+
+// URLKey identifies a URL: short codes are unique per domain ("" is the default domain)
+type URLKey struct {
+	Domain    string
+	ShortCode string
+}
+
+// Link statuses accepted by URLFilter.Status
+const (
+	URLStatusActive  = "active"  // Not expired and below its click limit
+	URLStatusExpired = "expired" // Past expires_at or out of clicks
+)
+
+// URLFilter narrows the links of a user returned by listings and aggregate analytics
+type URLFilter struct {
+	Archived      *bool   // true lists the archive, false the active links, nil both
+	Tag           string  // Only links carrying this tag (case-insensitive name)
+	FolderID      *string // Only links in this folder or any of its subfolders
+	Status        string  // URLStatusActive or URLStatusExpired, "" for both
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Domain        *string // Only links on this domain ("" is the default domain)
+	Search        string  // Free text matched against the short code, destination and title
+	Campaign      string  // Only links whose destination has this utm_campaign
+}
+
+// urlExpiredCondition matches links that no longer redirect because they expired or ran out of clicks
+const urlExpiredCondition = `((u.expires_at IS NOT NULL AND u.expires_at <= NOW()) OR (u.max_clicks IS NOT NULL AND u.click_count >= u.max_clicks))`
+
+// conditions returns the SQL conditions for the filter on the urls table aliased as u.
+// args holds the query arguments so far; the returned slice has the filter's arguments appended.
+func (f URLFilter) conditions(args []interface{}) (string, []interface{}) {
+	var where strings.Builder
+	if f.Archived != nil {
+		args = append(args, *f.Archived)
+		fmt.Fprintf(&where, " AND (u.archived_at IS NOT NULL) = $%d", len(args))
+	}
+	if f.Tag != "" {
+		args = append(args, f.Tag)
+		fmt.Fprintf(&where, ` AND EXISTS (
+			SELECT 1 FROM url_tags ut JOIN tags t ON t.id = ut.tag_id
+			WHERE ut.url_id = u.id AND LOWER(t.name) = LOWER($%d)
+		)`, len(args))
+	}
+	if f.FolderID != nil {
+		args = append(args, *f.FolderID)
+		fmt.Fprintf(&where, ` AND u.folder_id IN (
+			WITH RECURSIVE subfolders AS (
+				SELECT id FROM folders WHERE id = $%d AND user_id = u.user_id
+				UNION ALL
+				SELECT f.id FROM folders f JOIN subfolders s ON f.parent_id = s.id
+			)
+			SELECT id FROM subfolders
+		)`, len(args))
+	}
+	switch f.Status {
+	case URLStatusActive:
+		where.WriteString(" AND NOT " + urlExpiredCondition)
+	case URLStatusExpired:
+		where.WriteString(" AND " + urlExpiredCondition)
+	}
+	if f.CreatedAfter != nil {
+		args = append(args, f.CreatedAfter.UTC())
+		fmt.Fprintf(&where, " AND u.created_at >= $%d", len(args))
+	}
+	if f.CreatedBefore != nil {
+		args = append(args, f.CreatedBefore.UTC())
+		fmt.Fprintf(&where, " AND u.created_at < $%d", len(args))
+	}
+	if f.Domain != nil {
+		args = append(args, *f.Domain)
+		fmt.Fprintf(&where, " AND u.domain = $%d", len(args))
+	}
+	if f.Campaign != "" {
+		args = append(args, f.Campaign)
+		fmt.Fprintf(&where, " AND u.utm_campaign = $%d", len(args))
+	}
+	if query := searchTSQuery(f.Search); query != "" {
+		args = append(args, query)
+		fmt.Fprintf(&where, " AND u.search_vector @@ to_tsquery('simple', $%d)", len(args))
+	}
+	return where.String(), args
+}
+
+// searchTSQuery turns free text into a tsquery matching links that contain every word as a prefix
+// ("shop sale" becomes "shop:* & sale:*"). Punctuation separates words, so user input can't inject tsquery operators.
+func searchTSQuery(search string) string {
+	terms := strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
+// URL sort orders accepted by URLPage.Sort
+const (
+	URLSortCreated = "created"
+	URLSortClicks  = "clicks"
+	URLSortExpires = "expires"
+)
+
+// urlSortKeys maps each sort order to its SQL expression and the type its cursor value is cast to.
+// Links without an expiration sort as if they expired last.
+var urlSortKeys = map[string]struct{ expr, cast string }{
+	URLSortCreated: {expr: "u.created_at", cast: "timestamp"},
+	URLSortClicks:  {expr: "u.click_count", cast: "bigint"},
+	URLSortExpires: {expr: "COALESCE(u.expires_at, 'infinity'::timestamptz)", cast: "timestamptz"},
+}
+
+// URLCursor is the position of the last link of a page; the next page starts right after it
+type URLCursor struct {
+	Value string // Sort key of the link, formatted for Postgres
+	ID    string // Link ID, breaks ties between equal sort keys
+}
+
+// NewURLCursor returns the cursor positioned at url for the given sort order
+func NewURLCursor(sort string, url *entities.URL) URLCursor {
+	cursor := URLCursor{ID: url.ID}
+	switch sort {
+	case URLSortClicks:
+		cursor.Value = strconv.Itoa(url.ClickCount)
+	case URLSortExpires:
+		cursor.Value = "infinity"
+		if url.ExpiresAt != nil {
+			cursor.Value = url.ExpiresAt.UTC().Format(time.RFC3339Nano)
+		}
+	default:
+		// created_at has no time zone; keep the wall clock as stored
+		cursor.Value = url.CreatedAt.Format("2006-01-02T15:04:05.999999")
+	}
+	return cursor
+}
+
+// URLPage selects one page of a user's links
+type URLPage struct {
+	Sort  string     // One of the URLSort constants, URLSortCreated when empty
+	Desc  bool       // Largest sort keys first
+	Limit int        // Page size
+	After *URLCursor // Position of the last link of the previous page, nil for the first page
 }
 
 type urlRepository struct {
@@ -30,99 +214,439 @@ func NewURLRepository(db *sql.DB) URLRepository {
 	return &urlRepository{db: db}
 }
 
-// Create inserts a new URL into the database
-func (r *urlRepository) Create(shortCode, originalURL string, userID *string, expiresAt *time.Time) (*entities.URL, error) {
-	// Ensure expiresAt is stored in UTC
-	var expiresAtValue interface{}
-	if expiresAt != nil {
-		// Convert to UTC and use explicit UTC timestamp in SQL
-		utcTime := expiresAt.UTC()
-		expiresAtValue = utcTime
-	} else {
-		expiresAtValue = nil
-	}
+// urlColumns is the column list scanned by scanURL
+const urlColumns = `id, domain, short_code, original_url, user_id, click_count, created_at, expires_at, starts_at, redirect_type, password_hash, max_clicks, sticky_variants, deep_link,
+	forward_query, query_precedence, forward_path, deleted_at, archived_at, title, notes, metadata, folder_id`
 
-	query := `
-		INSERT INTO urls (short_code, original_url, user_id, expires_at)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, short_code, original_url, user_id, click_count, created_at, expires_at
-	`
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// isUniqueViolation reports whether err is a unique constraint violation (SQLSTATE 23505)
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// scanURL scans a single URL row selected with urlColumns
+func scanURL(row rowScanner) (*entities.URL, error) {
 	var url entities.URL
-	err := r.db.QueryRow(query, shortCode, originalURL, userID, expiresAtValue).Scan(
+	var deepLink, metadata []byte
+	err := row.Scan(
 		&url.ID,
+		&url.Domain,
 		&url.ShortCode,
 		&url.OriginalURL,
 		&url.UserID,
 		&url.ClickCount,
 		&url.CreatedAt,
 		&url.ExpiresAt,
+		&url.StartsAt,
+		&url.RedirectType,
+		&url.PasswordHash,
+		&url.MaxClicks,
+		&url.StickyVariants,
+		&deepLink,
+		&url.ForwardQuery,
+		&url.QueryPrecedence,
+		&url.ForwardPath,
+		&url.DeletedAt,
+		&url.ArchivedAt,
+		&url.Title,
+		&url.Notes,
+		&metadata,
+		&url.FolderID,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if deepLink != nil {
+		url.DeepLink = &entities.DeepLink{}
+		if err := json.Unmarshal(deepLink, url.DeepLink); err != nil {
+			return nil, fmt.Errorf("failed to decode deep link: %w", err)
+		}
+	}
+	if metadata != nil {
+		if err := json.Unmarshal(metadata, &url.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode metadata: %w", err)
+		}
+	}
+	return &url, nil
+}
 
+// deepLinkValue encodes an optional deep link configuration for a JSONB column
+func deepLinkValue(deepLink *entities.DeepLink) (interface{}, error) {
+	if deepLink == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(deepLink)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode deep link: %w", err)
+	}
+	return data, nil
+}
+
+// metadataValue encodes an optional metadata map for a JSONB column
+func metadataValue(metadata map[string]interface{}) (interface{}, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	return data, nil
+}
+
+// utcTimestamp converts an optional time to UTC for storage, keeping nil as NULL
+func utcTimestamp(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// destinationAuthority matches the scheme and authority of a URL ("HTTPS://Example.com:8080")
+var destinationAuthority = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://[^/?#]*`)
+
+// destinationHash returns the value of urls.destination_hash for a destination: the hex SHA-256 of the URL
+// with its scheme and host lowercased and a lone "/" path removed. Links without one are rehashed through
+// SetDestinationHash.
+func destinationHash(originalURL string) string {
+	authority := destinationAuthority.FindString(originalURL)
+	rest := originalURL[len(authority):]
+	if rest == "/" || strings.HasPrefix(rest, "/?") || strings.HasPrefix(rest, "/#") {
+		rest = rest[1:]
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(authority) + rest))
+	return hex.EncodeToString(sum[:])
+}
+
+// codeSkeleton returns the value of urls.code_skeleton for a short code: the skeleton of a Unicode code, NULL for
+// plain codes, which can only be spelled one way
+func codeSkeleton(shortCode string) interface{} {
+	if codefilter.IsPlain(shortCode) {
+		return nil
+	}
+	return codefilter.Skeleton(shortCode)
+}
+
+// utmCampaign returns the value of urls.utm_campaign for a destination: its utm_campaign parameter, nil when absent
+func utmCampaign(originalURL string) interface{} {
+	parsed, err := neturl.Parse(originalURL)
+	if err != nil {
+		return nil
+	}
+	campaign := parsed.Query().Get("utm_campaign")
+	if campaign == "" {
+		return nil
+	}
+	if runes := []rune(campaign); len(runes) > 255 {
+		campaign = string(runes[:255])
+	}
+	return campaign
+}
+
+// Create inserts a new URL into the database
+func (r *urlRepository) Create(url *entities.URL) (*entities.URL, error) {
+	return insertURL(r.db, url)
+}
+
+// CreateBatch inserts several URLs in one transaction. Each row runs under its own savepoint,
+// so a failing row (e.g. a taken short code) is reported in errs without aborting the others.
+// created[i] is nil exactly when errs[i] is set.
+func (r *urlRepository) CreateBatch(urls []*entities.URL) (created []*entities.URL, errs []error) {
+	created = make([]*entities.URL, len(urls))
+	errs = make([]error, len(urls))
+	fail := func(err error) ([]*entities.URL, []error) {
+		for i := range urls {
+			created[i], errs[i] = nil, err
+		}
+		return created, errs
+	}
+
+	tx, err := r.db.Begin()
 	if err != nil {
+		return fail(fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	for i, url := range urls {
+		if _, err := tx.Exec(`SAVEPOINT batch_row`); err != nil {
+			return fail(fmt.Errorf("failed to create savepoint: %w", err))
+		}
+		created[i], errs[i] = insertURL(tx, url)
+		release := `RELEASE SAVEPOINT batch_row`
+		if errs[i] != nil {
+			release = `ROLLBACK TO SAVEPOINT batch_row`
+		}
+		if _, err := tx.Exec(release); err != nil {
+			return fail(fmt.Errorf("failed to release savepoint: %w", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fail(fmt.Errorf("failed to commit URLs: %w", err))
+	}
+	return created, errs
+}
+
+// rowQuerier is implemented by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// insertURL inserts a URL through db or a transaction and returns the stored row.
+// A non-zero CreatedAt and ClickCount are kept (links imported from another shortener), otherwise defaults apply.
+// ErrShortCodeTaken is returned when the domain already has a link with the short code or one with the same skeleton.
+func insertURL(q rowQuerier, url *entities.URL) (*entities.URL, error) {
+	deepLink, err := deepLinkValue(url.DeepLink)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := metadataValue(url.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var createdAt interface{}
+	if !url.CreatedAt.IsZero() {
+		createdAt = url.CreatedAt.UTC()
+	}
+
+	// Ensure startsAt and expiresAt are stored in UTC. A code claimed on the default domain is taken out of the
+	// pool of pre-generated codes, so custom and imported codes are never handed out again.
+	query := `
+		WITH unpooled AS (
+			DELETE FROM short_code_pool WHERE $14 = '' AND short_code = $1
+		)
+		INSERT INTO urls (short_code, original_url, user_id, expires_at, starts_at, redirect_type, password_hash, max_clicks, sticky_variants, deep_link,
+			forward_query, query_precedence, forward_path, domain, title, notes, metadata, folder_id, click_count, created_at, destination_hash, utm_campaign,
+			code_skeleton)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, COALESCE($20::timestamp, CURRENT_TIMESTAMP), $21, $22, $23)
+		ON CONFLICT DO NOTHING
+		RETURNING ` + urlColumns
+
+	created, err := scanURL(q.QueryRow(query,
+		url.ShortCode,
+		url.OriginalURL,
+		url.UserID,
+		utcTimestamp(url.ExpiresAt),
+		utcTimestamp(url.StartsAt),
+		url.RedirectType,
+		url.PasswordHash,
+		url.MaxClicks,
+		url.StickyVariants,
+		deepLink,
+		url.ForwardQuery,
+		url.QueryPrecedence,
+		url.ForwardPath,
+		url.Domain,
+		url.Title,
+		url.Notes,
+		metadata,
+		url.FolderID,
+		url.ClickCount,
+		createdAt,
+		destinationHash(url.OriginalURL),
+		utmCampaign(url.OriginalURL),
+		codeSkeleton(url.ShortCode),
+	))
+	if err != nil {
+		// The insert arbitrates concurrent claims of a code and its lookalikes: the losers get no row back
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShortCodeTaken
+		}
 		return nil, fmt.Errorf("failed to create URL: %w", err)
 	}
 
-	return &url, nil
+	return created, nil
 }
 
-// FindByShortCode finds a URL by its short code (only if not expired)
-func (r *urlRepository) FindByShortCode(shortCode string) (*entities.URL, error) {
+// FindByShortCode finds a URL by its short code (only if not expired and under its click limit).
+// Links whose starts_at is still in the future return ErrURLNotYetActive.
+func (r *urlRepository) FindByShortCode(domain, shortCode string) (*entities.URL, error) {
 	query := `
-		SELECT id, short_code, original_url, user_id, click_count, created_at, expires_at
+		SELECT ` + urlColumns + `
 		FROM urls
-		WHERE short_code = $1
+		WHERE short_code = $1 AND domain = $2
+		AND deleted_at IS NULL
 		AND (expires_at IS NULL OR expires_at > (NOW() AT TIME ZONE 'UTC'))
+		AND (max_clicks IS NULL OR click_count < max_clicks)
 	`
 
-	var url entities.URL
-	err := r.db.QueryRow(query, shortCode).Scan(
-		&url.ID,
-		&url.ShortCode,
-		&url.OriginalURL,
-		&url.UserID,
-		&url.ClickCount,
-		&url.CreatedAt,
-		&url.ExpiresAt,
-	)
+	url, err := scanURL(r.db.QueryRow(query, shortCode, domain))
+	if err == sql.ErrNoRows {
+		return nil, ErrURLNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find URL: %w", err)
+	}
+
+	if url.StartsAt != nil && url.StartsAt.After(time.Now().UTC()) {
+		return nil, ErrURLNotYetActive
+	}
+
+	return url, nil
+}
 
+// FindReusableByDestination finds the most recent link of a user on a domain whose destination normalizes like
+// originalURL and that does nothing but redirect there with redirectType. Only links that currently redirect qualify:
+// not trashed, archived, expired, out of clicks or scheduled for later. Links with a password, click limit, schedule,
+// A/B destinations, deep link, routing rules or query/path forwarding are never reused.
+func (r *urlRepository) FindReusableByDestination(userID, domain, originalURL string, redirectType int) (*entities.URL, error) {
+	query := `
+		SELECT ` + urlColumns + `
+		FROM urls u
+		WHERE u.user_id = $1 AND u.domain = $2 AND u.destination_hash = $3
+		AND u.deleted_at IS NULL AND u.archived_at IS NULL
+		AND u.redirect_type = $4
+		AND COALESCE(u.password_hash, '') = '' AND u.max_clicks IS NULL
+		AND u.starts_at IS NULL AND u.expires_at IS NULL
+		AND u.deep_link IS NULL AND NOT u.sticky_variants AND NOT u.forward_query AND NOT u.forward_path
+		AND NOT EXISTS (SELECT 1 FROM url_destinations d WHERE d.url_id = u.id)
+		AND NOT EXISTS (SELECT 1 FROM url_routing_rules rr WHERE rr.url_id = u.id)
+		ORDER BY u.created_at DESC
+		LIMIT 1
+	`
+
+	url, err := scanURL(r.db.QueryRow(query, userID, domain, destinationHash(originalURL), redirectType))
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("URL not found or expired")
+		return nil, ErrURLNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find URL: %w", err)
 	}
+	return url, nil
+}
 
-	return &url, nil
+// ListWithoutDestinationHash retrieves up to limit links whose destination hash hasn't been computed yet
+func (r *urlRepository) ListWithoutDestinationHash(limit int) ([]*entities.URL, error) {
+	query := `
+		SELECT ` + urlColumns + `
+		FROM urls u
+		WHERE u.destination_hash IS NULL
+		ORDER BY u.id
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get URLs: %w", err)
+	}
+	defer rows.Close()
+
+	urls := []*entities.URL{}
+	for rows.Next() {
+		url, err := scanURL(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan URL: %w", err)
+		}
+		urls = append(urls, url)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating URLs: %w", err)
+	}
+
+	return urls, nil
 }
 
-// IncrementClickCount increments the click count for a URL and logs the click
-func (r *urlRepository) IncrementClickCount(shortCode string) error {
-	// First, get the URL ID
-	var urlID string
-	err := r.db.QueryRow("SELECT id FROM urls WHERE short_code = $1", shortCode).Scan(&urlID)
+// SetDestinationHash stores the hash of destination for a link that has none yet; links updated
+// in the meantime keep the hash written by Update
+func (r *urlRepository) SetDestinationHash(urlID, destination string) error {
+	_, err := r.db.Exec(`
+		UPDATE urls SET destination_hash = $1 WHERE id = $2 AND destination_hash IS NULL
+	`, destinationHash(destination), urlID)
 	if err != nil {
-		return fmt.Errorf("failed to find URL: %w", err)
+		return fmt.Errorf("failed to set destination hash: %w", err)
 	}
+	return nil
+}
 
-	// Update click count
-	_, err = r.db.Exec(`
-		UPDATE urls
-		SET click_count = click_count + 1
-		WHERE short_code = $1
-	`, shortCode)
+// ListWithoutCodeSkeleton retrieves up to limit links after afterID, in ID order, whose Unicode short code has no
+// skeleton yet. Pass the nil UUID to start from the first link.
+func (r *urlRepository) ListWithoutCodeSkeleton(afterID string, limit int) ([]*entities.URL, error) {
+	query := `
+		SELECT ` + urlColumns + `
+		FROM urls u
+		WHERE u.code_skeleton IS NULL AND u.short_code !~ '^[A-Za-z0-9_-]*$' AND u.id > $1
+		ORDER BY u.id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, afterID, limit)
 	if err != nil {
-		return fmt.Errorf("failed to increment click count: %w", err)
+		return nil, fmt.Errorf("failed to get URLs: %w", err)
 	}
+	defer rows.Close()
 
+	urls := []*entities.URL{}
+	for rows.Next() {
+		url, err := scanURL(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan URL: %w", err)
+		}
+		urls = append(urls, url)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating URLs: %w", err)
+	}
+
+	return urls, nil
+}
+
+// SetCodeSkeleton stores the skeleton of a link's short code.
+// ErrShortCodeTaken is returned when another link on the domain has a code with the same skeleton.
+func (r *urlRepository) SetCodeSkeleton(urlID, shortCode string) error {
+	_, err := r.db.Exec(`UPDATE urls SET code_skeleton = $1 WHERE id = $2`, codeSkeleton(shortCode), urlID)
+	if isUniqueViolation(err) {
+		return ErrShortCodeTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set code skeleton: %w", err)
+	}
+	return nil
+}
+
+// NextCodeSequence returns the next value of the sequence behind sequence-based short codes
+func (r *urlRepository) NextCodeSequence() (int64, error) {
+	var value int64
+	if err := r.db.QueryRow(`SELECT nextval('short_code_seq')`).Scan(&value); err != nil {
+		return 0, fmt.Errorf("failed to get next short code sequence value: %w", err)
+	}
+	return value, nil
+}
+
+// IncrementClickCount increments the click count for a URL and logs the click.
+// The limit check, increment and click log happen in one statement so concurrent
+// redirects can never push a link past its max_clicks.
+func (r *urlRepository) IncrementClickCount(domain, shortCode string, variantID *string) error {
 	// Log the click with timestamp in UTC
-	_, err = r.db.Exec(`
-		INSERT INTO url_clicks (url_id, clicked_at)
-		VALUES ($1, (NOW() AT TIME ZONE 'UTC'))
-	`, urlID)
+	query := `
+		WITH updated AS (
+			UPDATE urls
+			SET click_count = click_count + 1
+			WHERE short_code = $1 AND domain = $3
+			AND deleted_at IS NULL
+			AND (max_clicks IS NULL OR click_count < max_clicks)
+			RETURNING id
+		)
+		INSERT INTO url_clicks (url_id, clicked_at, variant_id)
+		SELECT id, (NOW() AT TIME ZONE 'UTC'), $2 FROM updated
+		RETURNING url_id
+	`
+
+	var urlID string
+	err := r.db.QueryRow(query, shortCode, variantID, domain).Scan(&urlID)
+	if err == sql.ErrNoRows {
+		return ErrClickLimitReached
+	}
 	if err != nil {
 		// Log the error with more context
-		log.Printf("ERROR: Failed to insert click for url_id=%s, short_code=%s: %v", urlID, shortCode, err)
+		log.Printf("ERROR: Failed to insert click for short_code=%s: %v", shortCode, err)
 		// Check if it's a table doesn't exist error
 		if err.Error() == "pq: relation \"url_clicks\" does not exist" {
 			log.Printf("ERROR: url_clicks table does not exist! Please run migrations.")
@@ -133,17 +657,18 @@ func (r *urlRepository) IncrementClickCount(shortCode string) error {
 	return nil
 }
 
-// Delete removes a URL from the database (only if user owns it or userID is nil)
-func (r *urlRepository) Delete(shortCode string, userID *string) error {
+// Delete moves a URL to the trash (only if user owns it or userID is nil).
+// Trashed URLs stop redirecting but keep their click history until purged.
+func (r *urlRepository) Delete(domain, shortCode string, userID *string) error {
 	var query string
 	var args []interface{}
 
 	if userID != nil {
-		query = `DELETE FROM urls WHERE short_code = $1 AND user_id = $2`
-		args = []interface{}{shortCode, *userID}
+		query = `UPDATE urls SET deleted_at = NOW() WHERE short_code = $1 AND domain = $2 AND user_id = $3 AND deleted_at IS NULL`
+		args = []interface{}{shortCode, domain, *userID}
 	} else {
-		query = `DELETE FROM urls WHERE short_code = $1`
-		args = []interface{}{shortCode}
+		query = `UPDATE urls SET deleted_at = NOW() WHERE short_code = $1 AND domain = $2 AND deleted_at IS NULL`
+		args = []interface{}{shortCode, domain}
 	}
 
 	result, err := r.db.Exec(query, args...)
@@ -163,39 +688,120 @@ func (r *urlRepository) Delete(shortCode string, userID *string) error {
 	return nil
 }
 
+// Restore takes a URL owned by the user out of the trash if it was deleted after deletedAfter
+func (r *urlRepository) Restore(domain, shortCode string, userID string, deletedAfter time.Time) (*entities.URL, error) {
+	query := `
+		UPDATE urls
+		SET deleted_at = NULL
+		WHERE short_code = $1 AND domain = $2 AND user_id = $3 AND deleted_at > $4
+		RETURNING ` + urlColumns
+
+	url, err := scanURL(r.db.QueryRow(query, shortCode, domain, userID, deletedAfter.UTC()))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("URL not found in trash")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore URL: %w", err)
+	}
+
+	return url, nil
+}
+
+// Purge permanently deletes a trashed URL owned by the user, including its click history
+func (r *urlRepository) Purge(domain, shortCode string, userID string) error {
+	result, err := r.db.Exec(`DELETE FROM urls WHERE short_code = $1 AND domain = $2 AND user_id = $3 AND deleted_at IS NOT NULL`, shortCode, domain, userID)
+	if err != nil {
+		return fmt.Errorf("failed to purge URL: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("URL not found in trash")
+	}
+
+	return nil
+}
+
+// PurgeDeleted permanently deletes every URL trashed before deletedBefore and returns their keys
+func (r *urlRepository) PurgeDeleted(deletedBefore time.Time) ([]URLKey, error) {
+	rows, err := r.db.Query(`DELETE FROM urls WHERE deleted_at < $1 RETURNING domain, short_code`, deletedBefore.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge URLs: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []URLKey
+	for rows.Next() {
+		var key URLKey
+		if err := rows.Scan(&key.Domain, &key.ShortCode); err != nil {
+			return nil, fmt.Errorf("failed to scan short code: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating purged URLs: %w", err)
+	}
+
+	return keys, nil
+}
+
+// GetTrashByUserID retrieves the trashed URLs of a user, most recently deleted first
+func (r *urlRepository) GetTrashByUserID(userID string) ([]*entities.URL, error) {
+	query := `
+		SELECT ` + urlColumns + `
+		FROM urls
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	return r.queryURLs(query, userID)
+}
+
+// SetArchived archives or unarchives a URL owned by the user
+func (r *urlRepository) SetArchived(domain, shortCode string, userID string, archived bool) error {
+	query := `
+		UPDATE urls
+		SET archived_at = CASE WHEN $4 THEN COALESCE(archived_at, NOW()) ELSE NULL END
+		WHERE short_code = $1 AND domain = $2 AND user_id = $3 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, shortCode, domain, userID, archived)
+	if err != nil {
+		return fmt.Errorf("failed to archive URL: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("URL not found or you don't have permission to update it")
+	}
+
+	return nil
+}
+
 // GetStats retrieves URL statistics (including expired URLs)
 // If userID is provided, only returns stats if the URL belongs to that user
-func (r *urlRepository) GetStats(shortCode string, userID *string) (*entities.URL, error) {
+func (r *urlRepository) GetStats(domain, shortCode string, userID *string) (*entities.URL, error) {
 	var query string
 	var args []interface{}
 
 	if userID != nil {
-		query = `
-			SELECT id, short_code, original_url, user_id, click_count, created_at, expires_at
-			FROM urls
-			WHERE short_code = $1 AND user_id = $2
-		`
-		args = []interface{}{shortCode, *userID}
+		query = `SELECT ` + urlColumns + ` FROM urls WHERE short_code = $1 AND domain = $2 AND user_id = $3 AND deleted_at IS NULL`
+		args = []interface{}{shortCode, domain, *userID}
 	} else {
-		query = `
-			SELECT id, short_code, original_url, user_id, click_count, created_at, expires_at
-			FROM urls
-			WHERE short_code = $1
-		`
-		args = []interface{}{shortCode}
+		query = `SELECT ` + urlColumns + ` FROM urls WHERE short_code = $1 AND domain = $2 AND deleted_at IS NULL`
+		args = []interface{}{shortCode, domain}
 	}
 
-	var url entities.URL
-	err := r.db.QueryRow(query, args...).Scan(
-		&url.ID,
-		&url.ShortCode,
-		&url.OriginalURL,
-		&url.UserID,
-		&url.ClickCount,
-		&url.CreatedAt,
-		&url.ExpiresAt,
-	)
-
+	url, err := scanURL(r.db.QueryRow(query, args...))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("URL not found")
 	}
@@ -203,19 +809,139 @@ func (r *urlRepository) GetStats(shortCode string, userID *string) (*entities.UR
 		return nil, fmt.Errorf("failed to get stats: %w", err)
 	}
 
-	return &url, nil
+	return url, nil
 }
 
-// GetByUserID retrieves all URLs for a specific user
-func (r *urlRepository) GetByUserID(userID string) ([]*entities.URL, error) {
+// GetByUserID retrieves one page of the URLs of a specific user matching the filter, excluding trashed ones,
+// along with the number of matching URLs across all pages
+func (r *urlRepository) GetByUserID(userID string, filter URLFilter, page URLPage) ([]*entities.URL, int, error) {
+	conditions, args := filter.conditions([]interface{}{userID})
+	where := `WHERE u.user_id = $1 AND u.deleted_at IS NULL` + conditions
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM urls u `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count URLs: %w", err)
+	}
+
+	key, ok := urlSortKeys[page.Sort]
+	if !ok {
+		key = urlSortKeys[URLSortCreated]
+	}
+	direction, comparison := "ASC", ">"
+	if page.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if page.After != nil {
+		args = append(args, page.After.Value, page.After.ID)
+		where += fmt.Sprintf(" AND (%s, u.id) %s ($%d::%s, $%d::uuid)", key.expr, comparison, len(args)-1, key.cast, len(args))
+	}
+	args = append(args, page.Limit)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM urls u
+		%s
+		ORDER BY %s %s, u.id %s
+		LIMIT $%d
+	`, urlColumns, where, key.expr, direction, direction, len(args))
+
+	urls, err := r.queryURLs(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return urls, total, nil
+}
+
+// StreamByUserID calls fn for every URL of the user matching the filter, oldest first, excluding trashed ones.
+// Rows are read as they arrive so exports don't hold the whole result in memory; each URL carries its tag names.
+func (r *urlRepository) StreamByUserID(userID string, filter URLFilter, fn func(url *entities.URL) error) error {
+	conditions, args := filter.conditions([]interface{}{userID})
 	query := `
-		SELECT id, short_code, original_url, user_id, click_count, created_at, expires_at
-		FROM urls
-		WHERE user_id = $1
-		ORDER BY created_at DESC
+		SELECT ` + urlColumns + `,
+			ARRAY(
+				SELECT t.name FROM url_tags ut JOIN tags t ON t.id = ut.tag_id
+				WHERE ut.url_id = u.id ORDER BY LOWER(t.name)
+			)
+		FROM urls u
+		WHERE u.user_id = $1 AND u.deleted_at IS NULL` + conditions + `
+		ORDER BY u.created_at ASC, u.id ASC
 	`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export URLs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tagNames []string
+		url, err := scanURL(extraColumns{row: rows, dest: []interface{}{pq.Array(&tagNames)}})
+		if err != nil {
+			return fmt.Errorf("failed to scan URL: %w", err)
+		}
+		url.Tags = make([]entities.Tag, len(tagNames))
+		for i, name := range tagNames {
+			url.Tags[i] = entities.Tag{Name: name}
+		}
+		if err := fn(url); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating URLs: %w", err)
+	}
+	return nil
+}
+
+// StreamClicks calls fn for every click of a URL within [from, to), oldest first; nil bounds are open
+func (r *urlRepository) StreamClicks(urlID string, from, to *time.Time, fn func(click *entities.URLClick) error) error {
+	query := `
+		SELECT c.id, c.url_id, c.clicked_at, c.variant_id, d.label
+		FROM url_clicks c
+		LEFT JOIN url_destinations d ON d.id = c.variant_id
+		WHERE c.url_id = $1
+			AND ($2::timestamp IS NULL OR c.clicked_at >= $2)
+			AND ($3::timestamp IS NULL OR c.clicked_at < $3)
+		ORDER BY c.clicked_at ASC, c.id ASC
+	`
+
+	rows, err := r.db.Query(query, urlID, utcTimestamp(from), utcTimestamp(to))
+	if err != nil {
+		return fmt.Errorf("failed to export clicks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var click entities.URLClick
+		if err := rows.Scan(&click.ID, &click.URLID, &click.ClickedAt, &click.VariantID, &click.VariantLabel); err != nil {
+			return fmt.Errorf("failed to scan click: %w", err)
+		}
+		if err := fn(&click); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating clicks: %w", err)
+	}
+	return nil
+}
+
+// extraColumns scans the columns expected by a scan helper followed by extra trailing columns
+type extraColumns struct {
+	row  rowScanner
+	dest []interface{}
+}
+
+func (e extraColumns) Scan(dest ...interface{}) error {
+	return e.row.Scan(append(dest, e.dest...)...)
+}
+
+// queryURLs runs a query selecting urlColumns and scans every row
+func (r *urlRepository) queryURLs(query string, args ...interface{}) ([]*entities.URL, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get URLs: %w", err)
 	}
@@ -223,20 +949,11 @@ func (r *urlRepository) GetByUserID(userID string) ([]*entities.URL, error) {
 
 	var urls []*entities.URL
 	for rows.Next() {
-		var url entities.URL
-		err := rows.Scan(
-			&url.ID,
-			&url.ShortCode,
-			&url.OriginalURL,
-			&url.UserID,
-			&url.ClickCount,
-			&url.CreatedAt,
-			&url.ExpiresAt,
-		)
+		url, err := scanURL(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan URL: %w", err)
 		}
-		urls = append(urls, &url)
+		urls = append(urls, url)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -246,78 +963,52 @@ func (r *urlRepository) GetByUserID(userID string) ([]*entities.URL, error) {
 	return urls, nil
 }
 
-// GetClickAnalytics retrieves click analytics grouped by time intervals
-func (r *urlRepository) GetClickAnalytics(urlID string, hours int) ([]map[string]interface{}, error) {
+// clickBucketExpr returns the SQL expression grouping url_clicks.clicked_at into time buckets
+// sized for the requested window
+func clickBucketExpr(hours int) string {
 	// DATE_TRUNC only accepts: minute, hour, day, week, month, etc.
 	// For custom intervals, we need to use a different approach
-	var query string
-	
 	switch {
 	case hours <= 6:
 		// Group by 10 minutes (all times in UTC)
-		query = fmt.Sprintf(`
-			SELECT 
-				(DATE_TRUNC('hour', clicked_at AT TIME ZONE 'UTC') + 
-				INTERVAL '10 minutes' * FLOOR(EXTRACT(MINUTE FROM clicked_at AT TIME ZONE 'UTC') / 10)) AT TIME ZONE 'UTC' as time_bucket,
-				COUNT(*) as click_count
-			FROM url_clicks
-			WHERE url_id = $1
-			AND clicked_at >= (NOW() AT TIME ZONE 'UTC') - INTERVAL '%d hours'
-			GROUP BY time_bucket
-			ORDER BY time_bucket ASC
-		`, hours)
+		return `(DATE_TRUNC('hour', clicked_at AT TIME ZONE 'UTC') +
+			INTERVAL '10 minutes' * FLOOR(EXTRACT(MINUTE FROM clicked_at AT TIME ZONE 'UTC') / 10)) AT TIME ZONE 'UTC'`
 	case hours <= 12:
 		// Group by 30 minutes (all times in UTC)
-		query = fmt.Sprintf(`
-			SELECT 
-				(DATE_TRUNC('hour', clicked_at AT TIME ZONE 'UTC') + 
-				INTERVAL '30 minutes' * FLOOR(EXTRACT(MINUTE FROM clicked_at AT TIME ZONE 'UTC') / 30)) AT TIME ZONE 'UTC' as time_bucket,
-				COUNT(*) as click_count
-			FROM url_clicks
-			WHERE url_id = $1
-			AND clicked_at >= (NOW() AT TIME ZONE 'UTC') - INTERVAL '%d hours'
-			GROUP BY time_bucket
-			ORDER BY time_bucket ASC
-		`, hours)
+		return `(DATE_TRUNC('hour', clicked_at AT TIME ZONE 'UTC') +
+			INTERVAL '30 minutes' * FLOOR(EXTRACT(MINUTE FROM clicked_at AT TIME ZONE 'UTC') / 30)) AT TIME ZONE 'UTC'`
 	case hours <= 24:
 		// Group by 1 hour (all times in UTC)
-		query = fmt.Sprintf(`
-			SELECT 
-				DATE_TRUNC('hour', clicked_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' as time_bucket,
-				COUNT(*) as click_count
-			FROM url_clicks
-			WHERE url_id = $1
-			AND clicked_at >= (NOW() AT TIME ZONE 'UTC') - INTERVAL '%d hours'
-			GROUP BY time_bucket
-			ORDER BY time_bucket ASC
-		`, hours)
+		return `DATE_TRUNC('hour', clicked_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'`
 	case hours <= 72: // 3 days
 		// Group by 6 hours - round to nearest 6 hour block (all times in UTC)
-		query = fmt.Sprintf(`
-			SELECT 
-				(DATE_TRUNC('day', clicked_at AT TIME ZONE 'UTC') + 
-				INTERVAL '6 hours' * FLOOR(EXTRACT(HOUR FROM clicked_at AT TIME ZONE 'UTC') / 6)) AT TIME ZONE 'UTC' as time_bucket,
-				COUNT(*) as click_count
-			FROM url_clicks
-			WHERE url_id = $1
-			AND clicked_at >= (NOW() AT TIME ZONE 'UTC') - INTERVAL '%d hours'
-			GROUP BY time_bucket
-			ORDER BY time_bucket ASC
-		`, hours)
+		return `(DATE_TRUNC('day', clicked_at AT TIME ZONE 'UTC') +
+			INTERVAL '6 hours' * FLOOR(EXTRACT(HOUR FROM clicked_at AT TIME ZONE 'UTC') / 6)) AT TIME ZONE 'UTC'`
 	default: // 7 days, 14 days, 30 days
 		// Group by 1 day (all times in UTC)
-		query = fmt.Sprintf(`
-			SELECT 
-				DATE_TRUNC('day', clicked_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' as time_bucket,
-				COUNT(*) as click_count
-			FROM url_clicks
-			WHERE url_id = $1
-			AND clicked_at >= (NOW() AT TIME ZONE 'UTC') - INTERVAL '%d hours'
-			GROUP BY time_bucket
-			ORDER BY time_bucket ASC
-		`, hours)
+		return `DATE_TRUNC('day', clicked_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'`
+	}
+}
+
+// GetClickAnalytics retrieves click analytics grouped by time intervals.
+// With byVariant, each bucket is further split by the A/B destination that was served.
+func (r *urlRepository) GetClickAnalytics(urlID string, hours int, byVariant bool) ([]map[string]interface{}, error) {
+	variantColumn := ""
+	if byVariant {
+		variantColumn = ", variant_id"
 	}
 
+	query := fmt.Sprintf(`
+		SELECT
+			%s as time_bucket%s,
+			COUNT(*) as click_count
+		FROM url_clicks
+		WHERE url_id = $1
+		AND clicked_at >= (NOW() AT TIME ZONE 'UTC') - INTERVAL '%d hours'
+		GROUP BY time_bucket%s
+		ORDER BY time_bucket ASC
+	`, clickBucketExpr(hours), variantColumn, hours, variantColumn)
+
 	rows, err := r.db.Query(query, urlID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get click analytics: %w", err)
@@ -327,11 +1018,60 @@ func (r *urlRepository) GetClickAnalytics(urlID string, hours int) ([]map[string
 	var analytics []map[string]interface{}
 	for rows.Next() {
 		var timeBucket time.Time
+		var variantID *string
 		var count int
-		if err := rows.Scan(&timeBucket, &count); err != nil {
+
+		dest := []interface{}{&timeBucket}
+		if byVariant {
+			dest = append(dest, &variantID)
+		}
+		dest = append(dest, &count)
+		if err := rows.Scan(dest...); err != nil {
 			return nil, fmt.Errorf("failed to scan analytics: %w", err)
 		}
 
+		bucket := map[string]interface{}{
+			"time":  timeBucket,
+			"count": count,
+		}
+		if byVariant {
+			bucket["variant_id"] = variantID
+		}
+		analytics = append(analytics, bucket)
+	}
+
+	return analytics, nil
+}
+
+// GetUserClickAnalytics retrieves click analytics across all links of a user matching the filter,
+// grouped by time intervals like GetClickAnalytics
+func (r *urlRepository) GetUserClickAnalytics(userID string, filter URLFilter, hours int) ([]map[string]interface{}, error) {
+	conditions, args := filter.conditions([]interface{}{userID})
+	query := fmt.Sprintf(`
+		SELECT
+			%s as time_bucket,
+			COUNT(*) as click_count
+		FROM url_clicks
+		JOIN urls u ON u.id = url_clicks.url_id
+		WHERE u.user_id = $1 AND u.deleted_at IS NULL%s
+		AND clicked_at >= (NOW() AT TIME ZONE 'UTC') - INTERVAL '%d hours'
+		GROUP BY time_bucket
+		ORDER BY time_bucket ASC
+	`, clickBucketExpr(hours), conditions, hours)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get click analytics: %w", err)
+	}
+	defer rows.Close()
+
+	var analytics []map[string]interface{}
+	for rows.Next() {
+		var timeBucket time.Time
+		var count int
+		if err := rows.Scan(&timeBucket, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan analytics: %w", err)
+		}
 		analytics = append(analytics, map[string]interface{}{
 			"time":  timeBucket,
 			"count": count,
@@ -341,30 +1081,180 @@ func (r *urlRepository) GetClickAnalytics(urlID string, hours int) ([]map[string
 	return analytics, nil
 }
 
-// UpdateExpiresAt updates the expiration date for a URL (only if user owns it)
-func (r *urlRepository) UpdateExpiresAt(shortCode string, userID *string, expiresAt *time.Time) error {
-	if userID == nil {
-		return fmt.Errorf("user ID required")
+// GetCampaignAnalytics groups the user's links by utm_campaign, with their number, lifetime clicks
+// and clicks within the window. Links without a campaign are left out.
+func (r *urlRepository) GetCampaignAnalytics(userID string, filter URLFilter, hours int) ([]map[string]interface{}, error) {
+	conditions, args := filter.conditions([]interface{}{userID})
+	query := fmt.Sprintf(`
+		SELECT
+			u.utm_campaign,
+			COUNT(*) as link_count,
+			COALESCE(SUM(u.click_count), 0) as total_clicks,
+			COALESCE(SUM(recent.click_count), 0) as click_count
+		FROM urls u
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) as click_count
+			FROM url_clicks
+			WHERE url_clicks.url_id = u.id
+			AND clicked_at >= (NOW() AT TIME ZONE 'UTC') - INTERVAL '%d hours'
+		) recent ON TRUE
+		WHERE u.user_id = $1 AND u.deleted_at IS NULL AND u.utm_campaign IS NOT NULL%s
+		GROUP BY u.utm_campaign
+		ORDER BY click_count DESC, total_clicks DESC, u.utm_campaign ASC
+	`, hours, conditions)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign analytics: %w", err)
 	}
+	defer rows.Close()
 
-	// Ensure expiresAt is stored in UTC
-	var expiresAtValue interface{}
-	if expiresAt != nil {
-		utcTime := expiresAt.UTC()
-		expiresAtValue = utcTime
-	} else {
-		expiresAtValue = nil
+	analytics := []map[string]interface{}{}
+	for rows.Next() {
+		var campaign string
+		var links, totalClicks, clicks int
+		if err := rows.Scan(&campaign, &links, &totalClicks, &clicks); err != nil {
+			return nil, fmt.Errorf("failed to scan campaign analytics: %w", err)
+		}
+		analytics = append(analytics, map[string]interface{}{
+			"campaign":     campaign,
+			"links":        links,
+			"total_clicks": totalClicks,
+			"count":        clicks,
+		})
 	}
 
-	query := `
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating campaign analytics: %w", err)
+	}
+
+	return analytics, nil
+}
+
+// GetVariantClickTotals retrieves the number of clicks each A/B destination received in the window
+func (r *urlRepository) GetVariantClickTotals(urlID string, hours int) ([]map[string]interface{}, error) {
+	query := fmt.Sprintf(`
+		SELECT d.id, d.label, d.url, d.weight, COUNT(c.id) as click_count
+		FROM url_destinations d
+		LEFT JOIN url_clicks c ON c.variant_id = d.id
+			AND c.clicked_at >= (NOW() AT TIME ZONE 'UTC') - INTERVAL '%d hours'
+		WHERE d.url_id = $1
+		GROUP BY d.id, d.label, d.url, d.weight, d.position
+		ORDER BY d.position ASC
+	`, hours)
+
+	rows, err := r.db.Query(query, urlID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variant analytics: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []map[string]interface{}
+	for rows.Next() {
+		var id, destination string
+		var label *string
+		var weight, count int
+		if err := rows.Scan(&id, &label, &destination, &weight, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan variant analytics: %w", err)
+		}
+
+		totals = append(totals, map[string]interface{}{
+			"variant_id": id,
+			"label":      label,
+			"url":        destination,
+			"weight":     weight,
+			"count":      count,
+		})
+	}
+
+	return totals, nil
+}
+
+// TransferOwner hands a link over to another user. The link leaves its folder and loses its tags,
+// since both belong to the previous owner.
+func (r *urlRepository) TransferOwner(domain, shortCode string, fromUserID, toUserID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var urlID string
+	err = tx.QueryRow(`
 		UPDATE urls
-		SET expires_at = $1
-		WHERE short_code = $2 AND user_id = $3
-	`
+		SET user_id = $1, folder_id = NULL
+		WHERE domain = $2 AND short_code = $3 AND user_id = $4 AND deleted_at IS NULL
+		RETURNING id
+	`, toUserID, domain, shortCode, fromUserID).Scan(&urlID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("URL not found or you don't have permission to transfer it")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to transfer URL: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM url_tags WHERE url_id = $1`, urlID); err != nil {
+		return fmt.Errorf("failed to clear link tags: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transfer: %w", err)
+	}
+	return nil
+}
 
-	result, err := r.db.Exec(query, expiresAtValue, shortCode, *userID)
+// Update persists the mutable fields of a URL (only if user owns it).
+// The previous version, including its A/B destinations, is saved to url_revisions in the same transaction.
+// A non-nil destinations replaces the A/B destinations as DestinationRepository.Replace does, also in that
+// transaction, and the saved rows are stored in url.Destinations.
+func (r *urlRepository) Update(url *entities.URL, changedBy *string, destinations *[]entities.URLDestination) error {
+	if url.UserID == nil {
+		return fmt.Errorf("user ID required")
+	}
+
+	deepLink, err := deepLinkValue(url.DeepLink)
 	if err != nil {
-		return fmt.Errorf("failed to update URL: %w", err)
+		return err
+	}
+	metadata, err := metadataValue(url.Metadata)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Snapshot the current row before it is overwritten
+	result, err := tx.Exec(`
+		INSERT INTO url_revisions (url_id, changed_by, original_url, starts_at, expires_at, redirect_type, password_hash,
+			max_clicks, sticky_variants, deep_link, forward_query, query_precedence, forward_path, destinations,
+			title, notes, metadata, folder_id, tags)
+		SELECT u.id, $3, u.original_url, u.starts_at, u.expires_at, u.redirect_type, u.password_hash,
+			u.max_clicks, u.sticky_variants, u.deep_link, u.forward_query, u.query_precedence, u.forward_path,
+			(
+				SELECT json_agg(json_build_object(
+					'id', d.id, 'position', d.position, 'url', d.url, 'weight', d.weight,
+					'label', d.label, 'created_at', d.created_at
+				) ORDER BY d.position)
+				FROM url_destinations d
+				WHERE d.url_id = u.id
+			),
+			u.title, u.notes, u.metadata, u.folder_id,
+			ARRAY(
+				SELECT t.name
+				FROM url_tags ut
+				JOIN tags t ON t.id = ut.tag_id
+				WHERE ut.url_id = u.id
+				ORDER BY LOWER(t.name)
+			)
+		FROM urls u
+		WHERE u.id = $1 AND u.user_id = $2 AND u.deleted_at IS NULL
+	`, url.ID, *url.UserID, changedBy)
+	if err != nil {
+		return fmt.Errorf("failed to save URL revision: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -376,6 +1266,50 @@ func (r *urlRepository) UpdateExpiresAt(shortCode string, userID *string, expire
 		return fmt.Errorf("URL not found or you don't have permission to update it")
 	}
 
+	// Ensure startsAt and expiresAt are stored in UTC
+	query := `
+		UPDATE urls
+		SET original_url = $1, expires_at = $2, starts_at = $3, redirect_type = $4, password_hash = $5, max_clicks = $6,
+			sticky_variants = $7, deep_link = $8, forward_query = $9, query_precedence = $10, forward_path = $11,
+			title = $12, notes = $13, metadata = $14, folder_id = $15, destination_hash = $16, utm_campaign = $17
+		WHERE id = $18 AND user_id = $19
+	`
+
+	if _, err := tx.Exec(query,
+		url.OriginalURL,
+		utcTimestamp(url.ExpiresAt),
+		utcTimestamp(url.StartsAt),
+		url.RedirectType,
+		url.PasswordHash,
+		url.MaxClicks,
+		url.StickyVariants,
+		deepLink,
+		url.ForwardQuery,
+		url.QueryPrecedence,
+		url.ForwardPath,
+		url.Title,
+		url.Notes,
+		metadata,
+		url.FolderID,
+		destinationHash(url.OriginalURL),
+		utmCampaign(url.OriginalURL),
+		url.ID,
+		*url.UserID,
+	); err != nil {
+		return fmt.Errorf("failed to update URL: %w", err)
+	}
+
+	if destinations != nil {
+		saved, err := replaceDestinations(tx, url.ID, *destinations)
+		if err != nil {
+			return err
+		}
+		url.Destinations = saved
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit URL update: %w", err)
+	}
+
 	return nil
 }
-
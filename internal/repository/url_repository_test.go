@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"shortly-be/internal/entities"
+
+	"github.com/lib/pq"
+)
+
+// fakeURLStore is a database/sql driver that answers the INSERT of insertURL like PostgreSQL does with the
+// unique indexes on (domain, short_code) and (domain, code_skeleton): ON CONFLICT DO NOTHING returns no row
+// for a taken code, an insert without it fails with a unique violation
+type fakeURLStore struct {
+	mu    sync.Mutex
+	codes map[string]bool // By domain and short code or code skeleton
+}
+
+func (s *fakeURLStore) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeURLConn{store: s}, nil
+}
+
+func (s *fakeURLStore) Driver() driver.Driver { return nil }
+
+type fakeURLConn struct {
+	store *fakeURLStore
+}
+
+func (c *fakeURLConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepared statements are not supported")
+}
+
+func (c *fakeURLConn) Close() error { return nil }
+
+func (c *fakeURLConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions are not supported")
+}
+
+func (c *fakeURLConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "INSERT INTO urls") {
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}
+	shortCode, _ := args[0].Value.(string)
+	domain, _ := args[13].Value.(string)
+	keys := []string{"code:" + domain + "/" + shortCode}
+	if skeleton, ok := args[22].Value.(string); ok {
+		keys = append(keys, "skeleton:"+domain+"/"+skeleton)
+	}
+
+	s := c.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		if !s.codes[key] {
+			continue
+		}
+		if strings.Contains(query, "ON CONFLICT DO NOTHING") {
+			return &fakeURLRows{columns: urlColumnNames()}, nil
+		}
+		return nil, &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"}
+	}
+	for _, key := range keys {
+		s.codes[key] = true
+	}
+
+	values := map[string]driver.Value{
+		"id":               fmt.Sprintf("url-%d", len(s.codes)),
+		"domain":           domain,
+		"short_code":       shortCode,
+		"original_url":     args[1].Value,
+		"user_id":          args[2].Value,
+		"click_count":      args[18].Value,
+		"created_at":       time.Now(),
+		"redirect_type":    args[5].Value,
+		"sticky_variants":  args[8].Value,
+		"forward_query":    args[10].Value,
+		"query_precedence": args[11].Value,
+		"forward_path":     args[12].Value,
+	}
+	row := make([]driver.Value, 0, len(values))
+	for _, column := range urlColumnNames() {
+		row = append(row, values[column])
+	}
+	return &fakeURLRows{columns: urlColumnNames(), rows: [][]driver.Value{row}}, nil
+}
+
+// urlColumnNames splits urlColumns into column names
+func urlColumnNames() []string {
+	var names []string
+	for _, column := range strings.Split(urlColumns, ",") {
+		names = append(names, strings.TrimSpace(column))
+	}
+	return names
+}
+
+type fakeURLRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeURLRows) Columns() []string { return r.columns }
+func (r *fakeURLRows) Close() error      { return nil }
+
+func (r *fakeURLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestCreateConcurrentShortCode(t *testing.T) {
+	db := sql.OpenDB(&fakeURLStore{codes: map[string]bool{}})
+	defer db.Close()
+	repo := NewURLRepository(db)
+
+	const requests = 8
+	errs := make([]error, requests)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = repo.Create(&entities.URL{
+				ShortCode:       "launch",
+				OriginalURL:     fmt.Sprintf("https://example.com/%d", i),
+				RedirectType:    302,
+				QueryPrecedence: "destination",
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	created, taken := 0, 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case errors.Is(err, ErrShortCodeTaken):
+			taken++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if created != 1 || taken != requests-1 {
+		t.Fatalf("created %d and rejected %d links, want 1 and %d", created, taken, requests-1)
+	}
+
+	// The code stays free on other domains
+	if _, err := repo.Create(&entities.URL{
+		Domain:          "go.example.com",
+		ShortCode:       "launch",
+		OriginalURL:     "https://example.com/other",
+		RedirectType:    302,
+		QueryPrecedence: "destination",
+	}); err != nil {
+		t.Fatalf("Create on another domain: %v", err)
+	}
+}
+
+func TestCreateLookalikeShortCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		code     string
+	}{
+		{name: "halfwidth katakana", existing: "カフェ", code: "ｶﾌｪ"},
+		{name: "latin alpha", existing: "abé", code: "ɑbé"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sql.OpenDB(&fakeURLStore{codes: map[string]bool{}})
+			defer db.Close()
+			repo := NewURLRepository(db)
+
+			newURL := func(domain, code string) *entities.URL {
+				return &entities.URL{
+					Domain:          domain,
+					ShortCode:       code,
+					OriginalURL:     "https://example.com/" + code,
+					RedirectType:    302,
+					QueryPrecedence: "destination",
+				}
+			}
+			if _, err := repo.Create(newURL("", tt.existing)); err != nil {
+				t.Fatalf("Create %q: %v", tt.existing, err)
+			}
+			if _, err := repo.Create(newURL("", tt.code)); !errors.Is(err, ErrShortCodeTaken) {
+				t.Fatalf("Create %q next to %q: err = %v, want ErrShortCodeTaken", tt.code, tt.existing, err)
+			}
+			if _, err := repo.Create(newURL("go.example.com", tt.code)); err != nil {
+				t.Fatalf("Create %q on another domain: %v", tt.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"shortly-be/internal/entities"
+
+	"github.com/lib/pq"
+)
+
+// ErrRevisionNotFound is returned when a revision does not exist for the given URL
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// RevisionRepository defines the interface for URL revision database operations.
+// Revisions are written by URLRepository.Update.
+type RevisionRepository interface {
+	ListByURLID(urlID string, limit int) ([]entities.URLRevision, error)
+	FindByID(urlID, revisionID string) (*entities.URLRevision, error)
+}
+
+type revisionRepository struct {
+	db *sql.DB
+}
+
+// NewRevisionRepository creates a new URL revision repository
+func NewRevisionRepository(db *sql.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+// revisionColumns is the column list scanned by scanRevision
+const revisionColumns = `id, url_id, changed_by, original_url, starts_at, expires_at, redirect_type, password_hash,
+	max_clicks, sticky_variants, deep_link, forward_query, query_precedence, forward_path, destinations,
+	title, notes, metadata, folder_id, tags, created_at`
+
+// scanRevision scans a single revision row selected with revisionColumns
+func scanRevision(row rowScanner) (*entities.URLRevision, error) {
+	var revision entities.URLRevision
+	var deepLink, destinations, metadata []byte
+	err := row.Scan(
+		&revision.ID,
+		&revision.URLID,
+		&revision.ChangedBy,
+		&revision.OriginalURL,
+		&revision.StartsAt,
+		&revision.ExpiresAt,
+		&revision.RedirectType,
+		&revision.PasswordHash,
+		&revision.MaxClicks,
+		&revision.StickyVariants,
+		&deepLink,
+		&revision.ForwardQuery,
+		&revision.QueryPrecedence,
+		&revision.ForwardPath,
+		&destinations,
+		&revision.Title,
+		&revision.Notes,
+		&metadata,
+		&revision.FolderID,
+		pq.Array(&revision.Tags),
+		&revision.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	revision.PasswordProtected = revision.PasswordHash != nil && *revision.PasswordHash != ""
+	if deepLink != nil {
+		revision.DeepLink = &entities.DeepLink{}
+		if err := json.Unmarshal(deepLink, revision.DeepLink); err != nil {
+			return nil, fmt.Errorf("failed to decode deep link: %w", err)
+		}
+	}
+	if destinations != nil {
+		if err := json.Unmarshal(destinations, &revision.Destinations); err != nil {
+			return nil, fmt.Errorf("failed to decode destinations: %w", err)
+		}
+	}
+	if metadata != nil {
+		if err := json.Unmarshal(metadata, &revision.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode metadata: %w", err)
+		}
+	}
+	return &revision, nil
+}
+
+// ListByURLID retrieves the most recent revisions of a URL, newest first
+func (r *revisionRepository) ListByURLID(urlID string, limit int) ([]entities.URLRevision, error) {
+	query := `
+		SELECT ` + revisionColumns + `
+		FROM url_revisions
+		WHERE url_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, urlID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []entities.URLRevision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, *revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// FindByID retrieves a single revision of a URL
+func (r *revisionRepository) FindByID(urlID, revisionID string) (*entities.URLRevision, error) {
+	query := `
+		SELECT ` + revisionColumns + `
+		FROM url_revisions
+		WHERE id = $1 AND url_id = $2
+	`
+
+	revision, err := scanRevision(r.db.QueryRow(query, revisionID, urlID))
+	if err == sql.ErrNoRows {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return revision, nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"shortly-be/internal/entities"
+
+	"github.com/lib/pq"
 )
 
 // UserRepository defines the interface for user database operations
@@ -12,6 +14,7 @@ type UserRepository interface {
 	Create(email, passwordHash string, name *string) (*entities.User, error)
 	FindByEmail(email string) (*entities.User, error)
 	FindByID(id string) (*entities.User, error)
+	UpdatePreferences(user *entities.User) error
 }
 
 type userRepository struct {
@@ -23,49 +26,51 @@ func NewUserRepository(db *sql.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-// Create inserts a new user into the database
-func (r *userRepository) Create(email, passwordHash string, name *string) (*entities.User, error) {
-	query := `
-		INSERT INTO users (email, password_hash, name)
-		VALUES ($1, $2, $3)
-		RETURNING id, email, password_hash, name, created_at, updated_at
-	`
+// userColumns is the column list scanned by scanUser
+const userColumns = `id, email, password_hash, name, default_redirect_type, tracking_params, strip_fragments, code_strategy, is_admin, created_at, updated_at`
 
+// scanUser scans a single user row selected with userColumns
+func scanUser(row rowScanner) (*entities.User, error) {
 	var user entities.User
-	err := r.db.QueryRow(query, email, passwordHash, name).Scan(
+	err := row.Scan(
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
 		&user.Name,
+		&user.DefaultRedirectType,
+		pq.Array(&user.TrackingParams),
+		&user.StripFragments,
+		&user.CodeStrategy,
+		&user.IsAdmin,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
 
+// Create inserts a new user into the database
+func (r *userRepository) Create(email, passwordHash string, name *string) (*entities.User, error) {
+	query := `
+		INSERT INTO users (email, password_hash, name)
+		VALUES ($1, $2, $3)
+		RETURNING ` + userColumns
+
+	user, err := scanUser(r.db.QueryRow(query, email, passwordHash, name))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 // FindByEmail finds a user by email
 func (r *userRepository) FindByEmail(email string) (*entities.User, error) {
-	query := `
-		SELECT id, email, password_hash, name, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
-
-	var user entities.User
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Name,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
 
+	user, err := scanUser(r.db.QueryRow(query, email))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
 	}
@@ -73,27 +78,14 @@ func (r *userRepository) FindByEmail(email string) (*entities.User, error) {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 // FindByID finds a user by ID (UUID)
 func (r *userRepository) FindByID(id string) (*entities.User, error) {
-	query := `
-		SELECT id, email, password_hash, name, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
-
-	var user entities.User
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Name,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
+	user, err := scanUser(r.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
 	}
@@ -101,6 +93,30 @@ func (r *userRepository) FindByID(id string) (*entities.User, error) {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
+// UpdatePreferences persists the user's link defaults
+func (r *userRepository) UpdatePreferences(user *entities.User) error {
+	query := `
+		UPDATE users
+		SET default_redirect_type = $1, tracking_params = $2, strip_fragments = $3, code_strategy = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5
+	`
+
+	result, err := r.db.Exec(query, user.DefaultRedirectType, pq.Array(user.TrackingParams), user.StripFragments, user.CodeStrategy, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update preferences: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
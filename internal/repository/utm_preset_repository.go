@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"shortly-be/internal/entities"
+)
+
+// ErrUTMPresetNotFound is returned when a UTM preset does not exist (or belongs to another user)
+var ErrUTMPresetNotFound = errors.New("UTM preset not found")
+
+// UTMPresetRepository defines the interface for UTM preset database operations
+type UTMPresetRepository interface {
+	ListByUserID(userID string) ([]entities.UTMPreset, error)
+	FindByID(userID, presetID string) (*entities.UTMPreset, error)
+	Create(preset *entities.UTMPreset) (*entities.UTMPreset, error)
+	Update(preset *entities.UTMPreset) (*entities.UTMPreset, error)
+	Delete(userID, presetID string) error
+}
+
+type utmPresetRepository struct {
+	db *sql.DB
+}
+
+// NewUTMPresetRepository creates a new UTM preset repository
+func NewUTMPresetRepository(db *sql.DB) UTMPresetRepository {
+	return &utmPresetRepository{db: db}
+}
+
+// utmPresetColumns is the column list scanned by scanUTMPreset
+const utmPresetColumns = `id, user_id, name, source, medium, campaign, term, content, created_at`
+
+// scanUTMPreset scans a single preset row selected with utmPresetColumns
+func scanUTMPreset(row rowScanner) (*entities.UTMPreset, error) {
+	var preset entities.UTMPreset
+	err := row.Scan(
+		&preset.ID,
+		&preset.UserID,
+		&preset.Name,
+		&preset.Source,
+		&preset.Medium,
+		&preset.Campaign,
+		&preset.Term,
+		&preset.Content,
+		&preset.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+// ListByUserID retrieves the UTM presets of a user in name order
+func (r *utmPresetRepository) ListByUserID(userID string) ([]entities.UTMPreset, error) {
+	query := `
+		SELECT ` + utmPresetColumns + `
+		FROM utm_presets
+		WHERE user_id = $1
+		ORDER BY LOWER(name) ASC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get UTM presets: %w", err)
+	}
+	defer rows.Close()
+
+	presets := []entities.UTMPreset{}
+	for rows.Next() {
+		preset, err := scanUTMPreset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan UTM preset: %w", err)
+		}
+		presets = append(presets, *preset)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating UTM presets: %w", err)
+	}
+
+	return presets, nil
+}
+
+// FindByID retrieves a UTM preset owned by the user
+func (r *utmPresetRepository) FindByID(userID, presetID string) (*entities.UTMPreset, error) {
+	query := `SELECT ` + utmPresetColumns + ` FROM utm_presets WHERE id = $1 AND user_id = $2`
+
+	preset, err := scanUTMPreset(r.db.QueryRow(query, presetID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrUTMPresetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get UTM preset: %w", err)
+	}
+
+	return preset, nil
+}
+
+// Create inserts a new UTM preset
+func (r *utmPresetRepository) Create(preset *entities.UTMPreset) (*entities.UTMPreset, error) {
+	query := `
+		INSERT INTO utm_presets (user_id, name, source, medium, campaign, term, content)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + utmPresetColumns
+
+	created, err := scanUTMPreset(r.db.QueryRow(query,
+		preset.UserID, preset.Name, preset.Source, preset.Medium, preset.Campaign, preset.Term, preset.Content))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("UTM preset '%s' already exists", preset.Name)
+		}
+		return nil, fmt.Errorf("failed to create UTM preset: %w", err)
+	}
+
+	return created, nil
+}
+
+// Update persists the name and parameters of a UTM preset owned by the user
+func (r *utmPresetRepository) Update(preset *entities.UTMPreset) (*entities.UTMPreset, error) {
+	query := `
+		UPDATE utm_presets
+		SET name = $1, source = $2, medium = $3, campaign = $4, term = $5, content = $6
+		WHERE id = $7 AND user_id = $8
+		RETURNING ` + utmPresetColumns
+
+	updated, err := scanUTMPreset(r.db.QueryRow(query,
+		preset.Name, preset.Source, preset.Medium, preset.Campaign, preset.Term, preset.Content, preset.ID, preset.UserID))
+	if err == sql.ErrNoRows {
+		return nil, ErrUTMPresetNotFound
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("UTM preset '%s' already exists", preset.Name)
+		}
+		return nil, fmt.Errorf("failed to update UTM preset: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes a UTM preset owned by the user; links created with it keep their parameters
+func (r *utmPresetRepository) Delete(userID, presetID string) error {
+	result, err := r.db.Exec(`DELETE FROM utm_presets WHERE id = $1 AND user_id = $2`, presetID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete UTM preset: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrUTMPresetNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package routing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/useragent"
+)
+
+// Visitor holds the request attributes routing rules can match on
+type Visitor struct {
+	Country  string       // ISO 3166-1 alpha-2, "" when unknown
+	Device   string       // One of useragent.Devices
+	OS       string       // One of useragent.OperatingSystems
+	Language language.Tag // Preferred Accept-Language tag, language.Und when absent
+	Time     time.Time
+}
+
+// NewVisitor builds a Visitor from raw request headers; country is resolved by the caller
+func NewVisitor(country, userAgent, acceptLanguage string, now time.Time) *Visitor {
+	info := useragent.Parse(userAgent)
+	visitor := &Visitor{
+		Country:  strings.ToUpper(country),
+		Device:   info.Device,
+		OS:       info.OS,
+		Language: language.Und,
+		Time:     now,
+	}
+
+	// ParseAcceptLanguage returns tags sorted by quality, the first one is the preferred language
+	if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(tags) > 0 {
+		visitor.Language = tags[0]
+	}
+
+	return visitor
+}
+
+// UsesCountry reports whether any rule needs the visitor's country, so GeoIP lookups can be skipped otherwise
+func UsesCountry(rules []entities.RoutingRule) bool {
+	for _, rule := range rules {
+		if len(rule.Conditions.Countries) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the first rule (in position order) whose conditions match the visitor, or nil
+func Select(rules []entities.RoutingRule, visitor *Visitor) *entities.RoutingRule {
+	for i := range rules {
+		if Matches(&rules[i].Conditions, visitor) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the visitor satisfies every condition that is set
+func Matches(c *entities.RuleConditions, visitor *Visitor) bool {
+	if len(c.Countries) > 0 && !contains(c.Countries, visitor.Country) {
+		return false
+	}
+	if len(c.Devices) > 0 && !contains(c.Devices, visitor.Device) {
+		return false
+	}
+	if len(c.OperatingSystems) > 0 && !contains(c.OperatingSystems, visitor.OS) {
+		return false
+	}
+	if len(c.Languages) > 0 && !matchesLanguage(c.Languages, visitor.Language) {
+		return false
+	}
+	if len(c.DaysOfWeek) > 0 || c.StartTime != "" {
+		return matchesSchedule(c, visitor.Time)
+	}
+	return true
+}
+
+// matchesLanguage compares base languages, and regions only when the rule specifies one
+func matchesLanguage(languages []string, tag language.Tag) bool {
+	if tag == language.Und {
+		return false
+	}
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+
+	for _, l := range languages {
+		ruleTag, err := language.Parse(l)
+		if err != nil {
+			continue
+		}
+		ruleBase, _ := ruleTag.Base()
+		if ruleBase != base {
+			continue
+		}
+		if ruleRegion, confidence := ruleTag.Region(); confidence == language.Exact && ruleRegion != region {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// matchesSchedule checks the day-of-week and time-of-day window in the rule's timezone
+func matchesSchedule(c *entities.RuleConditions, now time.Time) bool {
+	location := time.UTC
+	if c.Timezone != "" {
+		if loc, err := time.LoadLocation(c.Timezone); err == nil {
+			location = loc
+		}
+	}
+	local := now.In(location)
+
+	if len(c.DaysOfWeek) > 0 {
+		found := false
+		for _, day := range c.DaysOfWeek {
+			if time.Weekday(day) == local.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if c.StartTime != "" && c.EndTime != "" {
+		start, _ := parseClock(c.StartTime)
+		end, _ := parseClock(c.EndTime)
+		minute := local.Hour()*60 + local.Minute()
+		if start <= end {
+			return minute >= start && minute < end
+		}
+		// Window wraps past midnight (e.g. 22:00-06:00)
+		return minute >= start || minute < end
+	}
+
+	return true
+}
+
+var clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):([0-5][0-9])$`)
+
+// parseClock converts "HH:MM" into minutes since midnight
+func parseClock(value string) (int, error) {
+	parts := clockPattern.FindStringSubmatch(value)
+	if parts == nil {
+		return 0, fmt.Errorf("time must use the HH:MM format")
+	}
+	hours := int(parts[1][0]-'0')*10 + int(parts[1][1]-'0')
+	minutes := int(parts[2][0]-'0')*10 + int(parts[2][1]-'0')
+	return hours*60 + minutes, nil
+}
+
+// Normalize validates rule conditions and canonicalizes their casing in place
+func Normalize(c *entities.RuleConditions) error {
+	for i, country := range c.Countries {
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if len(country) != 2 {
+			return fmt.Errorf("invalid country code '%s', use ISO 3166-1 alpha-2 codes like DE", c.Countries[i])
+		}
+		c.Countries[i] = country
+	}
+
+	for i, device := range c.Devices {
+		device = strings.ToLower(strings.TrimSpace(device))
+		if !contains(useragent.Devices, device) {
+			return fmt.Errorf("invalid device '%s', must be one of %s", c.Devices[i], strings.Join(useragent.Devices, ", "))
+		}
+		c.Devices[i] = device
+	}
+
+	for i, os := range c.OperatingSystems {
+		os = strings.ToLower(strings.TrimSpace(os))
+		if !contains(useragent.OperatingSystems, os) {
+			return fmt.Errorf("invalid os '%s', must be one of %s", c.OperatingSystems[i], strings.Join(useragent.OperatingSystems, ", "))
+		}
+		c.OperatingSystems[i] = os
+	}
+
+	for i, l := range c.Languages {
+		tag, err := language.Parse(strings.TrimSpace(l))
+		if err != nil {
+			return fmt.Errorf("invalid language '%s'", l)
+		}
+		c.Languages[i] = tag.String()
+	}
+
+	for _, day := range c.DaysOfWeek {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("days of week must be between 0 (Sunday) and 6 (Saturday)")
+		}
+	}
+
+	if (c.StartTime == "") != (c.EndTime == "") {
+		return fmt.Errorf("start time and end time must be set together")
+	}
+	if c.StartTime != "" {
+		if _, err := parseClock(c.StartTime); err != nil {
+			return fmt.Errorf("invalid start time: %w", err)
+		}
+		if _, err := parseClock(c.EndTime); err != nil {
+			return fmt.Errorf("invalid end time: %w", err)
+		}
+		if c.StartTime == c.EndTime {
+			return fmt.Errorf("start time and end time must differ")
+		}
+	}
+
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone '%s'", c.Timezone)
+		}
+	}
+
+	if len(c.Countries) == 0 && len(c.Devices) == 0 && len(c.OperatingSystems) == 0 && len(c.Languages) == 0 &&
+		len(c.DaysOfWeek) == 0 && c.StartTime == "" {
+		return fmt.Errorf("a routing rule needs at least one condition")
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package routing
+
+import (
+	"math/rand/v2"
+
+	"shortly-be/internal/entities"
+)
+
+// PickDestination chooses an A/B destination by weight.
+// A sticky ID naming one of the destinations is honored so returning visitors see the same variant.
+func PickDestination(destinations []entities.URLDestination, stickyID string) *entities.URLDestination {
+	if len(destinations) == 0 {
+		return nil
+	}
+
+	total := 0
+	for i := range destinations {
+		if stickyID != "" && destinations[i].ID == stickyID {
+			return &destinations[i]
+		}
+		total += destinations[i].Weight
+	}
+	if total <= 0 {
+		return &destinations[0]
+	}
+
+	n := rand.IntN(total)
+	for i := range destinations {
+		n -= destinations[i].Weight
+		if n < 0 {
+			return &destinations[i]
+		}
+	}
+	return &destinations[len(destinations)-1]
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"shortly-be/internal/cache"
+	"shortly-be/internal/entities"
+	"shortly-be/internal/repository"
+)
+
+// TXTResolver looks up DNS TXT records. *net.Resolver satisfies it; tests can supply a fake.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// domainVerificationPrefix is prepended to the hostname for the TXT record checked during verification
+const domainVerificationPrefix = "_shortly-verify."
+
+// domainVerificationValue is the TXT record value prefix followed by the domain's token
+const domainVerificationValue = "shortly-verify="
+
+// DomainService defines the interface for managing custom branded domains
+type DomainService interface {
+	AddDomain(userID string, hostname string) (*entities.Domain, error)
+	ListDomains(userID string) ([]entities.Domain, error)
+	VerifyDomain(userID string, hostname string) (*entities.Domain, error)
+	DeleteDomain(userID string, hostname string) error
+}
+
+type domainService struct {
+	domainRepo repository.DomainRepository
+	resolver   TXTResolver
+	cache      cache.Cache
+	ctx        context.Context
+}
+
+// NewDomainService creates a new domain service
+func NewDomainService(domainRepo repository.DomainRepository, resolver TXTResolver, cacheClient cache.Cache) DomainService {
+	svc := &domainService{
+		domainRepo: domainRepo,
+		resolver:   resolver,
+		ctx:        context.Background(),
+	}
+	// Only set cache if provided (allows graceful degradation)
+	if cacheClient != nil {
+		svc.cache = cacheClient
+	}
+	return svc
+}
+
+// hostnamePattern matches a lowercase DNS hostname with at least two labels
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// normalizeHostname lowercases a hostname and strips any port and trailing dot
+func normalizeHostname(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// domainCacheKey returns the cache key recording whether a hostname is a verified custom domain
+func domainCacheKey(hostname string) string {
+	return fmt.Sprintf("domain:%s", hostname)
+}
+
+// AddDomain registers a custom domain for the user; it must be verified before links can use it
+func (s *domainService) AddDomain(userID string, hostname string) (*entities.Domain, error) {
+	hostname = normalizeHostname(hostname)
+	if len(hostname) > 253 || !hostnamePattern.MatchString(hostname) {
+		return nil, fmt.Errorf("'%s' is not a valid hostname", hostname)
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	return s.domainRepo.Create(&entities.Domain{
+		UserID:            userID,
+		Hostname:          hostname,
+		VerificationToken: hex.EncodeToString(token),
+	})
+}
+
+// ListDomains retrieves the custom domains of the user
+func (s *domainService) ListDomains(userID string) ([]entities.Domain, error) {
+	return s.domainRepo.ListByUserID(userID)
+}
+
+// VerifyDomain checks the domain's DNS TXT record (_shortly-verify.<hostname> = shortly-verify=<token>)
+// and marks it verified when the expected value is present
+func (s *domainService) VerifyDomain(userID string, hostname string) (*entities.Domain, error) {
+	domain, err := s.findOwnedDomain(userID, hostname)
+	if err != nil {
+		return nil, err
+	}
+	if domain.IsVerified() {
+		return domain, nil
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	records, err := s.resolver.LookupTXT(ctx, domainVerificationPrefix+domain.Hostname)
+	if err != nil {
+		return nil, fmt.Errorf("verification record not found: %w", err)
+	}
+
+	expected := domainVerificationValue + domain.VerificationToken
+	for _, record := range records {
+		if strings.TrimSpace(record) == expected {
+			verified, err := s.domainRepo.MarkVerified(domain.ID)
+			if err != nil {
+				return nil, err
+			}
+			if s.cache != nil {
+				s.cache.Delete(s.ctx, domainCacheKey(domain.Hostname))
+			}
+			return verified, nil
+		}
+	}
+
+	return nil, fmt.Errorf("verification record does not contain '%s'", expected)
+}
+
+// DeleteDomain removes a custom domain of the user that no longer has links
+func (s *domainService) DeleteDomain(userID string, hostname string) error {
+	hostname = normalizeHostname(hostname)
+	if err := s.domainRepo.Delete(hostname, userID); err != nil {
+		return err
+	}
+	if s.cache != nil {
+		s.cache.Delete(s.ctx, domainCacheKey(hostname))
+	}
+	return nil
+}
+
+// findOwnedDomain loads the user's claim on a hostname; claims of other users are never revealed
+func (s *domainService) findOwnedDomain(userID string, hostname string) (*entities.Domain, error) {
+	return s.domainRepo.FindByUserHostname(userID, normalizeHostname(hostname))
+}
+
+// isVerifiedDomain reports whether hostname is a verified custom domain, caching the answer briefly
+func isVerifiedDomain(ctx context.Context, domainRepo repository.DomainRepository, cacheClient cache.Cache, hostname string) (bool, error) {
+	if cacheClient != nil {
+		if val, err := cacheClient.Get(ctx, domainCacheKey(hostname)); err == nil && val != "" {
+			return val == "verified", nil
+		}
+	}
+
+	// Only verified domains are found by hostname
+	status := "verified"
+	_, err := domainRepo.FindByHostname(hostname)
+	if errors.Is(err, repository.ErrDomainNotFound) {
+		status = "unverified"
+	} else if err != nil {
+		return false, err
+	}
+
+	if cacheClient != nil {
+		cacheClient.Set(ctx, domainCacheKey(hostname), status, 5*time.Minute)
+	}
+	return status == "verified", nil
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/repository"
+)
+
+// fakeResolver serves TXT records from a map and records the names looked up
+type fakeResolver struct {
+	records map[string][]string
+	err     error
+	lookups []string
+}
+
+func (r *fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	r.lookups = append(r.lookups, name)
+	if r.err != nil {
+		return nil, r.err
+	}
+	records, ok := r.records[name]
+	if !ok {
+		return nil, fmt.Errorf("lookup %s: no such host", name)
+	}
+	return records, nil
+}
+
+// fakeDomainRepository keeps domains in memory with the semantics of the SQL repository
+type fakeDomainRepository struct {
+	mu      sync.Mutex
+	domains []*entities.Domain
+	nextID  int
+}
+
+func (r *fakeDomainRepository) Create(domain *entities.Domain) (*entities.Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.domains {
+		if existing.Hostname == domain.Hostname && (existing.IsVerified() || existing.UserID == domain.UserID) {
+			return nil, fmt.Errorf("domain '%s' is already registered", domain.Hostname)
+		}
+	}
+	r.nextID++
+	created := *domain
+	created.ID = fmt.Sprintf("domain-%d", r.nextID)
+	created.CreatedAt = time.Now()
+	r.domains = append(r.domains, &created)
+	return &created, nil
+}
+
+func (r *fakeDomainRepository) ListByUserID(userID string) ([]entities.Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	domains := []entities.Domain{}
+	for _, domain := range r.domains {
+		if domain.UserID == userID {
+			domains = append(domains, *domain)
+		}
+	}
+	return domains, nil
+}
+
+func (r *fakeDomainRepository) FindByHostname(hostname string) (*entities.Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, domain := range r.domains {
+		if domain.Hostname == hostname && domain.IsVerified() {
+			found := *domain
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrDomainNotFound
+}
+
+func (r *fakeDomainRepository) FindByUserHostname(userID string, hostname string) (*entities.Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, domain := range r.domains {
+		if domain.UserID == userID && domain.Hostname == hostname {
+			found := *domain
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrDomainNotFound
+}
+
+func (r *fakeDomainRepository) MarkVerified(domainID string) (*entities.Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var verified *entities.Domain
+	for _, domain := range r.domains {
+		if domain.ID == domainID {
+			verified = domain
+		}
+	}
+	if verified == nil {
+		return nil, repository.ErrDomainNotFound
+	}
+	if verified.VerifiedAt == nil {
+		now := time.Now()
+		verified.VerifiedAt = &now
+	}
+	kept := r.domains[:0]
+	for _, domain := range r.domains {
+		if domain.Hostname != verified.Hostname || domain.ID == verified.ID {
+			kept = append(kept, domain)
+		}
+	}
+	r.domains = kept
+	found := *verified
+	return &found, nil
+}
+
+func (r *fakeDomainRepository) Delete(hostname string, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, domain := range r.domains {
+		if domain.Hostname == hostname && domain.UserID == userID {
+			r.domains = append(r.domains[:i], r.domains[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrDomainNotFound
+}
+
+// verificationRecord returns the TXT record value that verifies a domain
+func verificationRecord(domain *entities.Domain) string {
+	return domainVerificationValue + domain.VerificationToken
+}
+
+func TestVerifyDomainWithMatchingRecord(t *testing.T) {
+	resolver := &fakeResolver{records: map[string][]string{}}
+	svc := NewDomainService(&fakeDomainRepository{}, resolver, nil)
+
+	domain, err := svc.AddDomain("user-1", "Go.Example.com.")
+	if err != nil {
+		t.Fatalf("AddDomain: %v", err)
+	}
+	if domain.Hostname != "go.example.com" {
+		t.Fatalf("hostname = %q, want go.example.com", domain.Hostname)
+	}
+	resolver.records["_shortly-verify.go.example.com"] = []string{"v=spf1 -all", " " + verificationRecord(domain) + " "}
+
+	verified, err := svc.VerifyDomain("user-1", "go.example.com")
+	if err != nil {
+		t.Fatalf("VerifyDomain: %v", err)
+	}
+	if !verified.IsVerified() {
+		t.Fatal("domain is not verified")
+	}
+	if len(resolver.lookups) != 1 || resolver.lookups[0] != "_shortly-verify.go.example.com" {
+		t.Fatalf("lookups = %v, want [_shortly-verify.go.example.com]", resolver.lookups)
+	}
+
+	// Verified domains are not looked up again
+	resolver.err = errors.New("resolver down")
+	if _, err := svc.VerifyDomain("user-1", "go.example.com"); err != nil {
+		t.Fatalf("VerifyDomain of a verified domain: %v", err)
+	}
+	if len(resolver.lookups) != 1 {
+		t.Fatalf("lookups = %v, want a single lookup", resolver.lookups)
+	}
+}
+
+func TestVerifyDomainWithoutMatchingRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		records func(domain *entities.Domain) []string // nil publishes no record
+		err     error
+	}{
+		{name: "lookup fails", err: errors.New("i/o timeout")},
+		{name: "no record"},
+		{name: "other token", records: func(*entities.Domain) []string {
+			return []string{domainVerificationValue + "0123456789abcdef"}
+		}},
+		{name: "token without prefix", records: func(domain *entities.Domain) []string {
+			return []string{domain.VerificationToken}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeDomainRepository{}
+			resolver := &fakeResolver{records: map[string][]string{}, err: tt.err}
+			svc := NewDomainService(repo, resolver, nil)
+
+			domain, err := svc.AddDomain("user-1", "example.com")
+			if err != nil {
+				t.Fatalf("AddDomain: %v", err)
+			}
+			if tt.records != nil {
+				resolver.records["_shortly-verify.example.com"] = tt.records(domain)
+			}
+
+			if _, err := svc.VerifyDomain("user-1", "example.com"); err == nil {
+				t.Fatal("VerifyDomain succeeded without a matching record")
+			}
+			claim, err := repo.FindByUserHostname("user-1", "example.com")
+			if err != nil {
+				t.Fatalf("FindByUserHostname: %v", err)
+			}
+			if claim.IsVerified() {
+				t.Fatal("domain was marked verified")
+			}
+		})
+	}
+}
+
+func TestVerifyDomainRemovesCompetingClaims(t *testing.T) {
+	repo := &fakeDomainRepository{}
+	resolver := &fakeResolver{records: map[string][]string{}}
+	svc := NewDomainService(repo, resolver, nil)
+
+	// A squatter's unverified claim doesn't block the real owner
+	if _, err := svc.AddDomain("squatter", "example.com"); err != nil {
+		t.Fatalf("AddDomain by squatter: %v", err)
+	}
+	owned, err := svc.AddDomain("owner", "example.com")
+	if err != nil {
+		t.Fatalf("AddDomain by owner: %v", err)
+	}
+	resolver.records["_shortly-verify.example.com"] = []string{verificationRecord(owned)}
+
+	if _, err := svc.VerifyDomain("squatter", "example.com"); err == nil {
+		t.Fatal("squatter verified the domain with the owner's record")
+	}
+	if _, err := svc.VerifyDomain("owner", "example.com"); err != nil {
+		t.Fatalf("VerifyDomain by owner: %v", err)
+	}
+
+	if _, err := svc.VerifyDomain("squatter", "example.com"); !errors.Is(err, repository.ErrDomainNotFound) {
+		t.Fatalf("squatter's claim after verification: err = %v, want ErrDomainNotFound", err)
+	}
+	if _, err := svc.AddDomain("squatter", "example.com"); err == nil {
+		t.Fatal("a verified domain was claimed again")
+	}
+	domains, err := svc.ListDomains("squatter")
+	if err != nil {
+		t.Fatalf("ListDomains: %v", err)
+	}
+	if len(domains) != 0 {
+		t.Fatalf("squatter still has %d domains", len(domains))
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+)
+
+// FolderService defines the interface for managing the folders users file their links in
+type FolderService interface {
+	ListFolders(userID string) ([]entities.Folder, error)
+	CreateFolder(userID string, req *models.FolderRequest) (*entities.Folder, error)
+	UpdateFolder(userID, folderID string, req *models.UpdateFolderRequest) (*entities.Folder, error)
+	DeleteFolder(userID, folderID string) error
+}
+
+type folderService struct {
+	folderRepo repository.FolderRepository
+}
+
+// NewFolderService creates a new folder service
+func NewFolderService(folderRepo repository.FolderRepository) FolderService {
+	return &folderService{
+		folderRepo: folderRepo,
+	}
+}
+
+// ListFolders retrieves the folders of the user; the tree is rebuilt by clients from parent_id
+func (s *folderService) ListFolders(userID string) ([]entities.Folder, error) {
+	return s.folderRepo.ListByUserID(userID)
+}
+
+// CreateFolder creates a folder, nested under parent_id when given
+func (s *folderService) CreateFolder(userID string, req *models.FolderRequest) (*entities.Folder, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("folder name is required")
+	}
+
+	parentID, err := s.resolveParent(userID, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.folderRepo.Create(&entities.Folder{
+		UserID:   userID,
+		ParentID: parentID,
+		Name:     name,
+	})
+}
+
+// UpdateFolder renames a folder of the user or moves it under another parent
+func (s *folderService) UpdateFolder(userID, folderID string, req *models.UpdateFolderRequest) (*entities.Folder, error) {
+	folder, err := s.folderRepo.FindByID(userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		folder.Name = strings.TrimSpace(*req.Name)
+		if folder.Name == "" {
+			return nil, fmt.Errorf("folder name is required")
+		}
+	}
+
+	if req.ParentID.Set {
+		folder.ParentID, err = s.resolveParent(userID, req.ParentID.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.folderRepo.Update(folder)
+}
+
+// DeleteFolder removes a folder of the user and its subfolders; their links move to the top level
+func (s *folderService) DeleteFolder(userID, folderID string) error {
+	return s.folderRepo.Delete(userID, folderID)
+}
+
+// resolveParent checks that a parent folder belongs to the user; nil or empty means top level
+func (s *folderService) resolveParent(userID string, parentID *string) (*string, error) {
+	if parentID == nil || *parentID == "" {
+		return nil, nil
+	}
+	parent, err := s.folderRepo.FindByID(userID, *parentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrFolderNotFound) {
+			return nil, fmt.Errorf("parent folder not found")
+		}
+		return nil, err
+	}
+	return &parent.ID, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/importer"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+)
+
+// JobService defines the interface for background jobs started through the API
+type JobService interface {
+	GetJob(userID, jobID string) (*entities.Job, error)
+	StartBulkCreate(userID string, rows []models.BulkCreateRow, baseURL string) (*entities.Job, error)
+	StartImport(userID string, records []importer.Record, opts *models.ImportOptions, baseURL string) (*entities.Job, error)
+	FailInterrupted() (int, error)
+	RunReaper(interval time.Duration)
+}
+
+const (
+	// jobHeartbeatInterval is how often a running job records that its process is alive
+	jobHeartbeatInterval = 30 * time.Second
+	// jobStaleAfter is how long a job may go without a heartbeat before it counts as interrupted
+	jobStaleAfter = 4 * jobHeartbeatInterval
+)
+
+// jobInterruptedError is the error of jobs whose process stopped before they finished
+const jobInterruptedError = "interrupted: the server stopped before the job finished, please start it again"
+
+type jobService struct {
+	jobRepo    repository.JobRepository
+	urlService URLService
+}
+
+// NewJobService creates a new job service.
+// Jobs run in goroutines of the process that started them and are not resumed after a restart;
+// RunReaper marks jobs whose process stopped as failed.
+func NewJobService(jobRepo repository.JobRepository, urlService URLService) JobService {
+	return &jobService{
+		jobRepo:    jobRepo,
+		urlService: urlService,
+	}
+}
+
+// GetJob retrieves a job of the user, including its result once it has finished
+func (s *jobService) GetJob(userID, jobID string) (*entities.Job, error) {
+	return s.jobRepo.FindByID(userID, jobID)
+}
+
+// StartBulkCreate records a bulk creation job and processes its rows in the background
+func (s *jobService) StartBulkCreate(userID string, rows []models.BulkCreateRow, baseURL string) (*entities.Job, error) {
+	job, err := s.jobRepo.Create(&entities.Job{
+		UserID: userID,
+		Kind:   entities.JobKindBulkCreate,
+		Total:  len(rows),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.runBulkCreate(job, rows, baseURL)
+
+	return job, nil
+}
+
+// runBulkCreate creates the links of a bulk creation job batch by batch, recording progress after each batch
+func (s *jobService) runBulkCreate(job *entities.Job, rows []models.BulkCreateRow, baseURL string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("ERROR: Bulk creation job %s panicked: %v", job.ID, r)
+			errMsg := fmt.Sprintf("internal error: %v", r)
+			s.finish(job.ID, entities.JobStatusFailed, nil, &errMsg)
+		}
+	}()
+	defer s.keepAlive(job.ID)()
+
+	response := &models.BulkCreateResponse{Results: make([]models.BulkCreateResult, 0, len(rows))}
+	for start := 0; start < len(rows); start += BulkBatchSize {
+		end := start + BulkBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		response.Add(s.urlService.CreateShortURLs(rows[start:end], &job.UserID, baseURL))
+
+		if err := s.jobRepo.UpdateProgress(job.ID, entities.JobStatusRunning, end, response.Failed); err != nil {
+			log.Printf("ERROR: Failed to record progress of job %s: %v", job.ID, err)
+		}
+	}
+
+	s.finish(job.ID, entities.JobStatusCompleted, response, nil)
+}
+
+// StartImport records an import job and imports its links in the background
+func (s *jobService) StartImport(userID string, records []importer.Record, opts *models.ImportOptions, baseURL string) (*entities.Job, error) {
+	job, err := s.jobRepo.Create(&entities.Job{
+		UserID: userID,
+		Kind:   entities.JobKindImport,
+		Total:  len(records),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.runImport(job, records, opts, baseURL)
+
+	return job, nil
+}
+
+// runImport imports the links of an import job batch by batch, recording progress after each batch.
+// Skipped links count as failed in the job's progress; the result tells them apart.
+func (s *jobService) runImport(job *entities.Job, records []importer.Record, opts *models.ImportOptions, baseURL string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("ERROR: Import job %s panicked: %v", job.ID, r)
+			errMsg := fmt.Sprintf("internal error: %v", r)
+			s.finish(job.ID, entities.JobStatusFailed, nil, &errMsg)
+		}
+	}()
+	defer s.keepAlive(job.ID)()
+
+	response := &models.ImportResponse{Results: make([]models.ImportResult, 0, len(records))}
+	for start := 0; start < len(records); start += BulkBatchSize {
+		end := start + BulkBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		results, err := s.urlService.ImportURLs(records[start:end], job.UserID, opts, baseURL)
+		if err != nil {
+			errMsg := err.Error()
+			s.finish(job.ID, entities.JobStatusFailed, nil, &errMsg)
+			return
+		}
+		response.Add(results)
+
+		if err := s.jobRepo.UpdateProgress(job.ID, entities.JobStatusRunning, end, response.Skipped+response.Failed); err != nil {
+			log.Printf("ERROR: Failed to record progress of job %s: %v", job.ID, err)
+		}
+	}
+
+	s.finish(job.ID, entities.JobStatusCompleted, response, nil)
+}
+
+// finish records the outcome of a job, logging when it can't be stored
+func (s *jobService) finish(jobID string, status string, result interface{}, errMsg *string) {
+	if err := s.jobRepo.Finish(jobID, status, result, errMsg); err != nil {
+		log.Printf("ERROR: Failed to finish job %s: %v", jobID, err)
+	}
+}
+
+// keepAlive records heartbeats for a job until the returned function is called
+func (s *jobService) keepAlive(jobID string) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(jobHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := s.jobRepo.Heartbeat(jobID); err != nil {
+					log.Printf("ERROR: Failed to record heartbeat of job %s: %v", jobID, err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// FailInterrupted marks pending and running jobs whose process stopped sending heartbeats as failed
+func (s *jobService) FailInterrupted() (int, error) {
+	return s.jobRepo.FailStale(time.Now().Add(-jobStaleAfter), jobInterruptedError)
+}
+
+// RunReaper fails interrupted jobs every interval; it never returns and is meant to run in its own goroutine
+func (s *jobService) RunReaper(interval time.Duration) {
+	if interval <= 0 {
+		interval = jobHeartbeatInterval
+	}
+	for {
+		failed, err := s.FailInterrupted()
+		if err != nil {
+			log.Printf("ERROR: Failed to fail interrupted jobs: %v", err)
+		} else if failed > 0 {
+			log.Printf("Marked %d interrupted jobs as failed", failed)
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"shortly-be/internal/codefilter"
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+)
+
+// ReservedWordService defines the interface for managing the words that can't be used as short codes
+type ReservedWordService interface {
+	ListWords() (*models.ReservedWordsResponse, error)
+	ReserveWord(userID string, req *models.ReservedWordRequest) (*entities.ReservedWord, error)
+	ReleaseWord(word string) error
+	Reload() error
+	RunReloader(interval time.Duration)
+}
+
+type reservedWordService struct {
+	wordRepo repository.ReservedWordRepository
+	filter   *codefilter.Filter
+}
+
+// NewReservedWordService creates a new reserved word service.
+// Changes take effect in filter immediately; other instances pick them up on their next reload.
+func NewReservedWordService(wordRepo repository.ReservedWordRepository, filter *codefilter.Filter) ReservedWordService {
+	return &reservedWordService{
+		wordRepo: wordRepo,
+		filter:   filter,
+	}
+}
+
+// reservedWordPattern matches the words that could be requested as short codes
+var reservedWordPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// ListWords retrieves the words reserved by admins and the reserved route paths
+func (s *reservedWordService) ListWords() (*models.ReservedWordsResponse, error) {
+	words, err := s.wordRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	return &models.ReservedWordsResponse{
+		Words:  words,
+		Routes: s.filter.Routes(),
+	}, nil
+}
+
+// ReserveWord reserves a word so it can't be used as a short code; links that already use it keep working
+func (s *reservedWordService) ReserveWord(userID string, req *models.ReservedWordRequest) (*entities.ReservedWord, error) {
+	word := strings.ToLower(strings.TrimSpace(req.Word))
+	if !reservedWordPattern.MatchString(word) {
+		return nil, fmt.Errorf("reserved words can only contain letters, numbers, hyphens, and underscores")
+	}
+	// Longer words can't be requested as short codes anyway
+	if len(word) > maxCustomCodeLength {
+		return nil, fmt.Errorf("reserved words must be at most %d characters long", maxCustomCodeLength)
+	}
+
+	created, err := s.wordRepo.Create(&entities.ReservedWord{
+		Word:      word,
+		CreatedBy: &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.filter.Reserve(created.Word)
+	return created, nil
+}
+
+// ReleaseWord makes a reserved word available as a short code again
+func (s *reservedWordService) ReleaseWord(word string) error {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if err := s.wordRepo.Delete(word); err != nil {
+		return err
+	}
+	s.filter.Release(word)
+	return nil
+}
+
+// Reload loads the reserved words into the filter
+func (s *reservedWordService) Reload() error {
+	words, err := s.wordRepo.List()
+	if err != nil {
+		return err
+	}
+	reserved := make([]string, len(words))
+	for i, word := range words {
+		reserved[i] = word.Word
+	}
+	s.filter.SetReserved(reserved)
+	return nil
+}
+
+// RunReloader reloads the reserved words every interval, picking up changes made through other instances;
+// it never returns and is meant to run in its own goroutine
+func (s *reservedWordService) RunReloader(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	for {
+		time.Sleep(interval)
+		if err := s.Reload(); err != nil {
+			log.Printf("ERROR: Failed to reload reserved words: %v", err)
+		}
+	}
+}
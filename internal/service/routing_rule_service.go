@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"shortly-be/internal/cache"
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+	"shortly-be/internal/routing"
+)
+
+// RoutingRuleService defines the interface for managing conditional routing rules of a URL
+type RoutingRuleService interface {
+	ListRules(domain, shortCode string, userID *string) ([]entities.RoutingRule, error)
+	CreateRule(domain, shortCode string, userID *string, req *models.RoutingRuleRequest) (*entities.RoutingRule, error)
+	UpdateRule(domain, shortCode string, userID *string, ruleID string, req *models.RoutingRuleRequest) (*entities.RoutingRule, error)
+	DeleteRule(domain, shortCode string, userID *string, ruleID string) error
+	ReorderRules(domain, shortCode string, userID *string, ruleIDs []string) ([]entities.RoutingRule, error)
+}
+
+type routingRuleService struct {
+	urlRepo  repository.URLRepository
+	ruleRepo repository.RoutingRuleRepository
+	cache    cache.Cache
+	ctx      context.Context
+}
+
+// NewRoutingRuleService creates a new routing rule service
+func NewRoutingRuleService(urlRepo repository.URLRepository, ruleRepo repository.RoutingRuleRepository, cacheClient cache.Cache) RoutingRuleService {
+	svc := &routingRuleService{
+		urlRepo:  urlRepo,
+		ruleRepo: ruleRepo,
+		ctx:      context.Background(),
+	}
+	// Only set cache if provided (allows graceful degradation)
+	if cacheClient != nil {
+		svc.cache = cacheClient
+	}
+	return svc
+}
+
+// ListRules retrieves the rules of a URL owned by the user in evaluation order
+func (s *routingRuleService) ListRules(domain, shortCode string, userID *string) ([]entities.RoutingRule, error) {
+	url, err := s.urlRepo.GetStats(domain, shortCode, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ruleRepo.ListByURLID(url.ID)
+}
+
+// CreateRule adds a routing rule to a URL owned by the user
+func (s *routingRuleService) CreateRule(domain, shortCode string, userID *string, req *models.RoutingRuleRequest) (*entities.RoutingRule, error) {
+	url, err := s.urlRepo.GetStats(domain, shortCode, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := req.Conditions
+	if err := routing.Normalize(&conditions); err != nil {
+		return nil, err
+	}
+
+	rule := &entities.RoutingRule{
+		URLID:       url.ID,
+		Destination: req.Destination,
+		Conditions:  conditions,
+	}
+	if req.Position != nil {
+		rule.Position = *req.Position
+	}
+
+	created, err := s.ruleRepo.Create(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateURL(domain, shortCode)
+	return created, nil
+}
+
+// UpdateRule replaces the destination and conditions of a rule, keeping its position unless a new one is given
+func (s *routingRuleService) UpdateRule(domain, shortCode string, userID *string, ruleID string, req *models.RoutingRuleRequest) (*entities.RoutingRule, error) {
+	url, err := s.urlRepo.GetStats(domain, shortCode, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.findRule(url.ID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := req.Conditions
+	if err := routing.Normalize(&conditions); err != nil {
+		return nil, err
+	}
+
+	rule.Destination = req.Destination
+	rule.Conditions = conditions
+	if req.Position != nil {
+		rule.Position = *req.Position
+	}
+
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return nil, err
+	}
+
+	s.invalidateURL(domain, shortCode)
+	return rule, nil
+}
+
+// DeleteRule removes a rule from a URL owned by the user
+func (s *routingRuleService) DeleteRule(domain, shortCode string, userID *string, ruleID string) error {
+	url, err := s.urlRepo.GetStats(domain, shortCode, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ruleRepo.Delete(url.ID, ruleID); err != nil {
+		return err
+	}
+
+	s.invalidateURL(domain, shortCode)
+	return nil
+}
+
+// ReorderRules sets the evaluation order of all rules of a URL owned by the user
+func (s *routingRuleService) ReorderRules(domain, shortCode string, userID *string, ruleIDs []string) ([]entities.RoutingRule, error) {
+	url, err := s.urlRepo.GetStats(domain, shortCode, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(ruleIDs))
+	for _, id := range ruleIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("rule '%s' is listed more than once", id)
+		}
+		seen[id] = true
+	}
+
+	if err := s.ruleRepo.Reorder(url.ID, ruleIDs); err != nil {
+		return nil, err
+	}
+
+	s.invalidateURL(domain, shortCode)
+	return s.ruleRepo.ListByURLID(url.ID)
+}
+
+// findRule looks up a single rule of a URL
+func (s *routingRuleService) findRule(urlID, ruleID string) (*entities.RoutingRule, error) {
+	rules, err := s.ruleRepo.ListByURLID(urlID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		if rules[i].ID == ruleID {
+			return &rules[i], nil
+		}
+	}
+	return nil, fmt.Errorf("routing rule not found")
+}
+
+// invalidateURL removes the cached redirect data so rule changes apply immediately
+func (s *routingRuleService) invalidateURL(domain, shortCode string) {
+	if s.cache == nil {
+		return
+	}
+	s.cache.Delete(s.ctx, urlCacheKey(domain, shortCode))
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+)
+
+// TagService defines the interface for managing the tags users put on their links
+type TagService interface {
+	ListTags(userID string) ([]entities.Tag, error)
+	CreateTag(userID string, req *models.TagRequest) (*entities.Tag, error)
+	UpdateTag(userID, tagID string, req *models.UpdateTagRequest) (*entities.Tag, error)
+	DeleteTag(userID, tagID string) error
+}
+
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+// NewTagService creates a new tag service
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{
+		tagRepo: tagRepo,
+	}
+}
+
+// ListTags retrieves the tags of the user
+func (s *tagService) ListTags(userID string) ([]entities.Tag, error) {
+	return s.tagRepo.ListByUserID(userID)
+}
+
+// CreateTag creates a tag; tags are also created on the fly when attached to a link
+func (s *tagService) CreateTag(userID string, req *models.TagRequest) (*entities.Tag, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("tag name is required")
+	}
+
+	return s.tagRepo.Create(&entities.Tag{
+		UserID: userID,
+		Name:   name,
+		Color:  req.Color,
+	})
+}
+
+// UpdateTag renames or recolors a tag of the user
+func (s *tagService) UpdateTag(userID, tagID string, req *models.UpdateTagRequest) (*entities.Tag, error) {
+	tag, err := s.tagRepo.FindByID(userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		tag.Name = strings.TrimSpace(*req.Name)
+		if tag.Name == "" {
+			return nil, fmt.Errorf("tag name is required")
+		}
+	}
+
+	if req.Color.Set {
+		tag.Color = req.Color.Value
+	}
+
+	return s.tagRepo.Update(tag)
+}
+
+// DeleteTag removes a tag of the user from every link
+func (s *tagService) DeleteTag(userID, tagID string) error {
+	return s.tagRepo.Delete(userID, tagID)
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"shortly-be/internal/cache"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+)
+
+// TrashService defines the interface for managing deleted (trashed) URLs
+type TrashService interface {
+	ListTrash(userID string) ([]*models.URLStatsResponse, error)
+	RestoreURL(domain, shortCode string, userID string) (*models.URLStatsResponse, error)
+	PurgeURL(domain, shortCode string, userID string) error
+	PurgeExpired() (int, error)
+	RunPurger(interval time.Duration)
+}
+
+type trashService struct {
+	urlRepo   repository.URLRepository
+	retention time.Duration
+	cache     cache.Cache
+	ctx       context.Context
+}
+
+// NewTrashService creates a new trash service.
+// Trashed URLs can be restored for the retention period and are purged afterwards.
+func NewTrashService(urlRepo repository.URLRepository, retention time.Duration, cacheClient cache.Cache) TrashService {
+	svc := &trashService{
+		urlRepo:   urlRepo,
+		retention: retention,
+		ctx:       context.Background(),
+	}
+	// Only set cache if provided (allows graceful degradation)
+	if cacheClient != nil {
+		svc.cache = cacheClient
+	}
+	return svc
+}
+
+// ListTrash retrieves the trashed URLs of a user along with their purge time
+func (s *trashService) ListTrash(userID string) ([]*models.URLStatsResponse, error) {
+	urls, err := s.urlRepo.GetTrashByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*models.URLStatsResponse, len(urls))
+	for i, url := range urls {
+		responses[i] = newURLStatsResponse(url)
+		if url.DeletedAt != nil {
+			purgeAt := url.DeletedAt.Add(s.retention)
+			responses[i].PurgeAt = &purgeAt
+		}
+	}
+
+	return responses, nil
+}
+
+// RestoreURL takes a URL owned by the user out of the trash while it is within the retention period
+func (s *trashService) RestoreURL(domain, shortCode string, userID string) (*models.URLStatsResponse, error) {
+	url, err := s.urlRepo.Restore(domain, shortCode, userID, time.Now().Add(-s.retention))
+	if err != nil {
+		return nil, err
+	}
+
+	// The code is live again
+	if s.cache != nil {
+		cacheKey := shortCodeExistsKey(domain, shortCode)
+		s.cache.Set(s.ctx, cacheKey, "taken", 1*time.Hour)
+	}
+
+	return newURLStatsResponse(url), nil
+}
+
+// PurgeURL permanently deletes a trashed URL owned by the user
+func (s *trashService) PurgeURL(domain, shortCode string, userID string) error {
+	if err := s.urlRepo.Purge(domain, shortCode, userID); err != nil {
+		return err
+	}
+	s.releaseShortCodes([]repository.URLKey{{Domain: domain, ShortCode: shortCode}})
+	return nil
+}
+
+// PurgeExpired permanently deletes URLs that have been in the trash longer than the retention period
+func (s *trashService) PurgeExpired() (int, error) {
+	keys, err := s.urlRepo.PurgeDeleted(time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, err
+	}
+	s.releaseShortCodes(keys)
+	return len(keys), nil
+}
+
+// RunPurger purges expired trash every interval; it never returns and is meant to run in its own goroutine
+func (s *trashService) RunPurger(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	for {
+		purged, err := s.PurgeExpired()
+		if err != nil {
+			log.Printf("ERROR: Failed to purge trashed URLs: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d trashed URLs", purged)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// releaseShortCodes drops cached state for purged short codes so they can be reused
+func (s *trashService) releaseShortCodes(keys []repository.URLKey) {
+	if s.cache == nil {
+		return
+	}
+	for _, key := range keys {
+		s.cache.Delete(s.ctx, urlCacheKey(key.Domain, key.ShortCode))
+		s.cache.Delete(s.ctx, shortCodeExistsKey(key.Domain, key.ShortCode))
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+)
+
+// maxBatchFilterMatches caps the number of links a filtered batch action may change at once
+const maxBatchFilterMatches = 10000
+
+// BatchUpdateURLs applies one action to the listed links of the user, or to all of their links matching
+// the filter. Each link succeeds or fails on its own; the response lists the outcome per link.
+func (s *urlService) BatchUpdateURLs(userID string, req *models.BatchRequest) (*models.BatchResponse, error) {
+	if (len(req.Links) > 0) == (req.Filter != nil) {
+		return nil, fmt.Errorf("either links or filter must be given")
+	}
+
+	apply, err := s.batchAction(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.BatchResponse{Action: req.Action, Results: []models.BatchItemResult{}}
+	record := func(domain, shortCode string, err error) {
+		result := models.BatchItemResult{Domain: domain, ShortCode: shortCode}
+		if err != nil {
+			result.Error = err.Error()
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	if req.Filter != nil {
+		urls, err := s.matchingURLs(userID, req.Filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, url := range urls {
+			record(url.Domain, url.ShortCode, apply(url))
+		}
+		return response, nil
+	}
+
+	for _, link := range req.Links {
+		domain := normalizeHostname(link.Domain)
+		shortCode := strings.TrimSpace(link.ShortCode)
+		url, err := s.repo.GetStats(domain, shortCode, &userID)
+		if err == nil {
+			err = apply(url)
+		}
+		record(domain, shortCode, err)
+	}
+	return response, nil
+}
+
+// matchingURLs returns every link of the user matching a listing filter
+func (s *urlService) matchingURLs(userID string, query *models.URLListQuery) ([]*entities.URL, error) {
+	filter, err := s.urlFilter(userID, query)
+	if err != nil {
+		return nil, err
+	}
+	archived := query.Archived
+	filter.Archived = &archived
+
+	urls, total, err := s.repo.GetByUserID(userID, filter, repository.URLPage{
+		Sort:  repository.URLSortCreated,
+		Limit: maxBatchFilterMatches,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if total > maxBatchFilterMatches {
+		return nil, fmt.Errorf("filter matches %d links, at most %d can be changed at once", total, maxBatchFilterMatches)
+	}
+	return urls, nil
+}
+
+// batchAction validates the parameters of a batch action once and returns the function applying it to one link
+func (s *urlService) batchAction(userID string, req *models.BatchRequest) (func(url *entities.URL) error, error) {
+	switch req.Action {
+	case models.BatchActionDelete:
+		return func(url *entities.URL) error {
+			return s.DeleteURL(url.Domain, url.ShortCode, &userID)
+		}, nil
+
+	case models.BatchActionArchive, models.BatchActionUnarchive:
+		archived := req.Action == models.BatchActionArchive
+		return func(url *entities.URL) error {
+			return s.ArchiveURL(url.Domain, url.ShortCode, userID, archived)
+		}, nil
+
+	case models.BatchActionSetExpiry:
+		expiresAt, err := parseOptionalTime(req.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		if expiresAt != nil && expiresAt.Before(time.Now()) {
+			return nil, fmt.Errorf("expiration time cannot be in the past")
+		}
+		update := &models.UpdateURLRequest{ExpiresAt: models.Optional[string]{Set: true, Value: req.ExpiresAt}}
+		return func(url *entities.URL) error {
+			return s.UpdateURL(url.Domain, url.ShortCode, &userID, update)
+		}, nil
+
+	case models.BatchActionAddTag, models.BatchActionRemoveTag:
+		tag := strings.TrimSpace(req.Tag)
+		if tag == "" {
+			return nil, fmt.Errorf("tag is required")
+		}
+		if req.Action == models.BatchActionRemoveTag {
+			return func(url *entities.URL) error {
+				return s.tags.RemoveURLTag(url.ID, userID, tag)
+			}, nil
+		}
+		return func(url *entities.URL) error {
+			return s.tags.AddURLTag(url.ID, userID, tag)
+		}, nil
+
+	case models.BatchActionMoveFolder:
+		folderID, err := s.resolveFolder(req.FolderID, &userID)
+		if err != nil {
+			return nil, err
+		}
+		update := &models.UpdateURLRequest{FolderID: models.Optional[string]{Set: true, Value: folderID}}
+		return func(url *entities.URL) error {
+			return s.UpdateURL(url.Domain, url.ShortCode, &userID, update)
+		}, nil
+
+	case models.BatchActionTransferOwner:
+		if req.NewOwnerEmail == "" {
+			return nil, fmt.Errorf("new_owner_email is required")
+		}
+		newOwner, err := s.userRepo.FindByEmail(req.NewOwnerEmail)
+		if err != nil {
+			return nil, err
+		}
+		if newOwner.ID == userID {
+			return nil, fmt.Errorf("links already belong to this user")
+		}
+		return func(url *entities.URL) error {
+			// Custom domains stay with their owner, so their links can't change hands
+			if url.Domain != "" {
+				return fmt.Errorf("links on custom domains can't be transferred")
+			}
+			if err := s.repo.TransferOwner(url.Domain, url.ShortCode, userID, newOwner.ID); err != nil {
+				return err
+			}
+			s.invalidateURL(url.Domain, url.ShortCode)
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown batch action '%s'", req.Action)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+)
+
+// BulkBatchSize is the number of rows of a bulk creation inserted per transaction
+const BulkBatchSize = 100
+
+// CreateShortURLs creates links in bulk. Every row is validated like CreateShortURL and
+// valid rows are inserted BulkBatchSize at a time, each batch in one transaction.
+// A failing row doesn't affect the others; each row gets its own result, in input order.
+func (s *urlService) CreateShortURLs(rows []models.BulkCreateRow, userID *string, baseURL string) []models.BulkCreateResult {
+	results := make([]models.BulkCreateResult, 0, len(rows))
+	for start := 0; start < len(rows); start += BulkBatchSize {
+		end := start + BulkBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		results = append(results, s.createBatch(rows[start:end], userID, baseURL)...)
+	}
+	return results
+}
+
+// createBatch validates a batch of rows and inserts the valid ones in one transaction
+func (s *urlService) createBatch(rows []models.BulkCreateRow, userID *string, baseURL string) []models.BulkCreateResult {
+	results := make([]models.BulkCreateResult, len(rows))
+
+	var pending []*newURL
+	var positions []int // Index in rows of each pending link
+	for i, row := range rows {
+		results[i].Row = row.Row
+		if row.Error != "" {
+			results[i].Error = row.Error
+			continue
+		}
+		if row.Request.ReuseExisting {
+			existing, err := s.findReusableURL(row.Request, userID, baseURL)
+			if err != nil || existing != nil {
+				results[i].URL = existing
+				if err != nil {
+					results[i].Error = err.Error()
+				}
+				continue
+			}
+		}
+		prepared, err := s.prepareURL(row.Request, userID)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		pending = append(pending, prepared)
+		positions = append(positions, i)
+	}
+	if len(pending) == 0 {
+		return results
+	}
+
+	responses, errs := s.insertPrepared(pending, baseURL)
+	for i := range pending {
+		result := &results[positions[i]]
+		if errs[i] != nil {
+			result.Error = errs[i].Error()
+			continue
+		}
+		result.URL = responses[i]
+	}
+
+	return results
+}
+
+// insertPrepared inserts prepared links in one transaction and completes each inserted one like CreateShortURL.
+// responses[i] is nil exactly when errs[i] is set.
+func (s *urlService) insertPrepared(pending []*newURL, baseURL string) (responses []*models.CreateURLResponse, errs []error) {
+	urls := make([]*entities.URL, len(pending))
+	for i, prepared := range pending {
+		urls[i] = prepared.url
+	}
+
+	responses = make([]*models.CreateURLResponse, len(pending))
+	created, errs := s.repo.CreateBatch(urls)
+	for i, prepared := range pending {
+		created[i], errs[i] = s.retryTakenCode(prepared, created[i], errs[i])
+		if errs[i] != nil {
+			errs[i] = s.insertError(prepared.url, errs[i])
+			continue
+		}
+		responses[i], errs[i] = s.finishURL(created[i], prepared, baseURL)
+	}
+
+	return responses, errs
+}
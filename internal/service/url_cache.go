@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"shortly-be/internal/entities"
+)
+
+// urlCacheEntry is the value stored under the url:<code> cache key.
+// It holds everything the redirect path needs so cache hits never touch the database.
+type urlCacheEntry struct {
+	ID                string                    `json:"id"`
+	OriginalURL       string                    `json:"original_url"`
+	StartsAt          *time.Time                `json:"starts_at"`
+	ExpiresAt         *time.Time                `json:"expires_at"`
+	RedirectType      int                       `json:"redirect_type"`
+	PasswordProtected bool                      `json:"password_protected"`
+	PasswordVersion   string                    `json:"password_version,omitempty"`
+	MaxClicks         *int                      `json:"max_clicks"`
+	StickyVariants    bool                      `json:"sticky_variants"`
+	Rules             []entities.RoutingRule    `json:"rules,omitempty"`
+	Destinations      []entities.URLDestination `json:"destinations,omitempty"`
+	DeepLink          *entities.DeepLink        `json:"deep_link,omitempty"`
+	ForwardQuery      bool                      `json:"forward_query,omitempty"`
+	QueryPrecedence   string                    `json:"query_precedence,omitempty"`
+	ForwardPath       bool                      `json:"forward_path,omitempty"`
+}
+
+// urlCacheKey returns the cache key holding the redirect data of a short code.
+// Links on the default domain keep the url:<code> key; custom domains use url:<domain>:<code>.
+func urlCacheKey(domain, shortCode string) string {
+	if domain == "" {
+		return fmt.Sprintf("url:%s", shortCode)
+	}
+	return fmt.Sprintf("url:%s:%s", domain, shortCode)
+}
+
+// shortCodeExistsKey returns the cache key recording whether a short code is taken on a domain
+func shortCodeExistsKey(domain, shortCode string) string {
+	if domain == "" {
+		return fmt.Sprintf("shortcode:exists:%s", shortCode)
+	}
+	return fmt.Sprintf("shortcode:exists:%s:%s", domain, shortCode)
+}
+
+// shortCodeReservationKey returns the cache key of a short code claimed by a link being created
+func shortCodeReservationKey(domain, shortCode string) string {
+	if domain == "" {
+		return fmt.Sprintf("shortcode:reserved:%s", shortCode)
+	}
+	return fmt.Sprintf("shortcode:reserved:%s:%s", domain, shortCode)
+}
+
+// newURLCacheEntry builds the cached redirect data for a URL
+func newURLCacheEntry(url *entities.URL, rules []entities.RoutingRule) *urlCacheEntry {
+	return &urlCacheEntry{
+		ID:                url.ID,
+		OriginalURL:       url.OriginalURL,
+		StartsAt:          url.StartsAt,
+		ExpiresAt:         url.ExpiresAt,
+		RedirectType:      url.RedirectType,
+		PasswordProtected: url.IsPasswordProtected(),
+		PasswordVersion:   url.PasswordVersion(),
+		MaxClicks:         url.MaxClicks,
+		StickyVariants:    url.StickyVariants,
+		Rules:             rules,
+		Destinations:      url.Destinations,
+		DeepLink:          url.DeepLink,
+		ForwardQuery:      url.ForwardQuery,
+		QueryPrecedence:   url.QueryPrecedence,
+		ForwardPath:       url.ForwardPath,
+	}
+}
+
+// cacheURL stores the redirect data for a URL in the cache
+func (s *urlService) cacheURL(url *entities.URL, rules []entities.RoutingRule) {
+	if s.cache == nil {
+		return
+	}
+	s.cache.SetJSON(s.ctx, urlCacheKey(url.Domain, url.ShortCode), newURLCacheEntry(url, rules), 1*time.Hour)
+}
+
+// invalidateURL removes the cached redirect data for a short code
+func (s *urlService) invalidateURL(domain, shortCode string) {
+	if s.cache == nil {
+		return
+	}
+	s.cache.Delete(s.ctx, urlCacheKey(domain, shortCode))
+}
+
+// lookupURL returns the redirect data for a short code from cache, falling back to the database.
+// The boolean reports whether the entry came from the cache.
+func (s *urlService) lookupURL(domain, shortCode string) (*urlCacheEntry, bool, error) {
+	// Try cache first (if available)
+	if s.cache != nil {
+		var cached urlCacheEntry
+		err := s.cache.GetJSON(s.ctx, urlCacheKey(domain, shortCode), &cached)
+		// Entries written before redirect_type or the password version was cached are treated as a miss
+		if err == nil && cached.OriginalURL != "" && cached.RedirectType != 0 && (!cached.PasswordProtected || cached.PasswordVersion != "") {
+			if cached.ExpiresAt == nil || cached.ExpiresAt.After(time.Now()) {
+				return &cached, true, nil
+			}
+			// Expired, remove from cache and check DB
+			s.invalidateURL(domain, shortCode)
+		}
+	}
+
+	// Cache miss or expired, get from database
+	url, err := s.repo.FindByShortCode(domain, shortCode)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rules, err := s.ruleRepo.ListByURLID(url.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	url.Destinations, err = s.destRepo.ListByURLID(url.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Cache the result
+	entry := newURLCacheEntry(url, rules)
+	if s.cache != nil {
+		s.cache.SetJSON(s.ctx, urlCacheKey(domain, shortCode), entry, 1*time.Hour)
+	}
+
+	return entry, false, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	neturl "net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// defaultPorts are the ports dropped from destinations because their scheme implies them
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// canonicalDestination normalizes the destination of a new link with the owner's settings:
+// the user's tracking parameters (or the deployment default) and fragment preference
+func (s *urlService) canonicalDestination(raw string, userID *string) (string, error) {
+	trackingParams := s.trackingParams
+	stripFragment := false
+	if userID != nil && s.userRepo != nil {
+		user, err := s.userRepo.FindByID(*userID)
+		if err != nil {
+			return "", fmt.Errorf("failed to load user preferences: %w", err)
+		}
+		if user.TrackingParams != nil {
+			trackingParams = user.TrackingParams
+		}
+		stripFragment = user.StripFragments
+	}
+	return canonicalizeURL(raw, trackingParams, stripFragment)
+}
+
+// canonicalizeURL rewrites a URL so that equivalent spellings become identical: the scheme and host are lowercased,
+// internationalized hosts converted to punycode, default ports dropped and query parameters sorted by name.
+// Parameters matching trackingParams (case-insensitive, "utm_*" matches a prefix) are removed, and so is the
+// fragment when stripFragment is set. The path and the encoding of parameters are kept as they are.
+func canonicalizeURL(raw string, trackingParams []string, stripFragment bool) (string, error) {
+	parsed, err := neturl.Parse(raw)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return "", fmt.Errorf("invalid URL '%s'", raw)
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+
+	host := strings.ToLower(parsed.Hostname())
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		host = ascii
+	}
+	port := parsed.Port()
+	if port == defaultPorts[parsed.Scheme] {
+		port = ""
+	}
+	if port != "" {
+		parsed.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		parsed.Host = "[" + host + "]" // IPv6 literal
+	} else {
+		parsed.Host = host
+	}
+
+	parsed.RawQuery = canonicalQuery(parsed.RawQuery, trackingParams)
+	parsed.ForceQuery = false
+	if stripFragment {
+		parsed.Fragment = ""
+		parsed.RawFragment = ""
+	}
+
+	return parsed.String(), nil
+}
+
+// canonicalQuery sorts the parameters of a raw query string by name, keeping the order of repeated
+// parameters, and drops empty pairs and tracking parameters
+func canonicalQuery(rawQuery string, trackingParams []string) string {
+	type param struct{ name, pair string }
+	var params []param
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := neturl.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if isTrackingParam(name, trackingParams) {
+			continue
+		}
+		params = append(params, param{name: name, pair: pair})
+	}
+
+	sort.SliceStable(params, func(i, j int) bool { return params[i].name < params[j].name })
+	pairs := make([]string, len(params))
+	for i, p := range params {
+		pairs[i] = p.pair
+	}
+	return strings.Join(pairs, "&")
+}
+
+// isTrackingParam reports whether a query parameter name matches one of the tracking parameter patterns
+func isTrackingParam(name string, trackingParams []string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range trackingParams {
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == pattern {
+			return true
+		}
+	}
+	return false
+}
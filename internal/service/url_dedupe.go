@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+)
+
+// findReusableURL returns the user's active link to the requested destination for a reuse_existing creation,
+// or nil when a new link has to be created. Only plain redirects with the requested redirect type are reused:
+// requests for a custom short code, a password, a click limit, a schedule, A/B destinations, a deep link or
+// query/path forwarding, and requests from anonymous users, always create a link. Title, notes, metadata,
+// folder and tags of the request are ignored when a link is reused.
+func (s *urlService) findReusableURL(req *models.CreateURLRequest, userID *string, baseURL string) (*models.CreateURLResponse, error) {
+	if userID == nil || (req.ShortCode != nil && *req.ShortCode != "") || !requestsPlainRedirect(req) {
+		return nil, nil
+	}
+
+	destination, err := s.linkDestination(req, userID)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := s.resolveLinkDomain(req.Domain, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	redirectType, err := s.resolveRedirectType(req.RedirectType, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := s.repo.FindReusableByDestination(*userID, domain, destination, redirectType)
+	if errors.Is(err, repository.ErrURLNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	url.Destinations, err = s.destRepo.ListByURLID(url.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load destinations: %w", err)
+	}
+	if err := s.loadTags([]*entities.URL{url}); err != nil {
+		return nil, err
+	}
+
+	response := newCreateURLResponse(url, baseURL)
+	response.Reused = true
+	return response, nil
+}
+
+// requestsPlainRedirect reports whether a creation request asks for nothing but a redirect to its destination
+func requestsPlainRedirect(req *models.CreateURLRequest) bool {
+	return (req.Password == nil || *req.Password == "") &&
+		req.MaxClicks == nil &&
+		req.StartsAt == nil && req.ExpiresAt == nil &&
+		len(req.Destinations) == 0 && !req.StickyVariants &&
+		req.DeepLink == nil &&
+		!req.ForwardQuery && !req.ForwardPath
+}
+
+// destinationHashBatchSize is the number of links rehashed per query by BackfillDestinationHashes
+const destinationHashBatchSize = 500
+
+// BackfillDestinationHashes computes the destination hash of links that have none, from their destination
+// canonicalized with the owner's settings like a new link's, and returns the number of links hashed.
+// Destinations that can't be canonicalized are hashed as stored.
+func (s *urlService) BackfillDestinationHashes() (int, error) {
+	hashed := 0
+	for {
+		urls, err := s.repo.ListWithoutDestinationHash(destinationHashBatchSize)
+		if err != nil {
+			return hashed, err
+		}
+		if len(urls) == 0 {
+			return hashed, nil
+		}
+
+		for _, url := range urls {
+			destination, err := s.canonicalDestination(url.OriginalURL, url.UserID)
+			if err != nil {
+				log.Printf("Warning: Hashing destination of link %s as stored: %v", url.ID, err)
+				destination = url.OriginalURL
+			}
+			if err := s.repo.SetDestinationHash(url.ID, destination); err != nil {
+				return hashed, err
+			}
+			hashed++
+		}
+	}
+}
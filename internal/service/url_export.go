@@ -0,0 +1,27 @@
+package service
+
+import (
+	"time"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+)
+
+// ExportURLs calls fn for every link of the user (active and archived) matching the listing filters, oldest first.
+// Links are streamed from the database, so exports of any size use constant memory.
+func (s *urlService) ExportURLs(userID string, query *models.URLListQuery, fn func(url *entities.URL) error) error {
+	filter, err := s.urlFilter(userID, query)
+	if err != nil {
+		return err
+	}
+	return s.repo.StreamByUserID(userID, filter, fn)
+}
+
+// ExportClicks calls fn for every click on a link owned by the user within [from, to), oldest first
+func (s *urlService) ExportClicks(domain, shortCode string, userID *string, from, to *time.Time, fn func(click *entities.URLClick) error) error {
+	url, err := s.repo.GetStats(domain, shortCode, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.StreamClicks(url.ID, from, to, fn)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"shortly-be/internal/codefilter"
+	"shortly-be/internal/importer"
+	"shortly-be/internal/models"
+)
+
+// maxImportTitleLength matches the size of urls.title; longer titles of other shorteners are cut
+const maxImportTitleLength = 255
+
+// ImportURLs creates the links read from another shortener's export for a user.
+// Original short codes are kept unless they are invalid, reserved or already taken; such links are
+// skipped or get a generated code depending on opts.OnConflict, and the conflict is reported.
+// Links are inserted BulkBatchSize at a time; the error is only set when the import can't run at all.
+func (s *urlService) ImportURLs(records []importer.Record, userID string, opts *models.ImportOptions, baseURL string) ([]models.ImportResult, error) {
+	// Resolve the domain once so a bad domain fails the import instead of every link
+	domain, err := s.resolveLinkDomain(opts.Domain, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.ImportResult, 0, len(records))
+	seen := make(map[string]bool) // Codes claimed earlier in this import
+	for start := 0; start < len(records); start += BulkBatchSize {
+		end := start + BulkBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		results = append(results, s.importBatch(records[start:end], userID, domain, opts, seen, baseURL)...)
+	}
+	return results, nil
+}
+
+// importBatch resolves the short codes of a batch of records and inserts the importable ones in one transaction
+func (s *urlService) importBatch(records []importer.Record, userID, domain string, opts *models.ImportOptions, seen map[string]bool, baseURL string) []models.ImportResult {
+	results := make([]models.ImportResult, len(records))
+
+	var pending []*newURL
+	var positions []int // Index in records of each pending link
+	for i, record := range records {
+		result := &results[i]
+		result.Row = record.Row
+		result.OriginalCode = record.ShortCode
+		if record.Error != "" {
+			result.Status = models.ImportStatusFailed
+			result.Reason = record.Error
+			continue
+		}
+		if !isValidDestinationURL(record.URL) {
+			result.Status = models.ImportStatusFailed
+			result.Reason = fmt.Sprintf("invalid url '%s'", record.URL)
+			continue
+		}
+
+		result.Status = models.ImportStatusCreated
+		shortCode := codefilter.Normalize(record.ShortCode)
+		if shortCode != "" {
+			conflict, err := s.importCodeConflict(domain, shortCode, seen)
+			if err != nil {
+				result.Status = models.ImportStatusFailed
+				result.Reason = err.Error()
+				continue
+			}
+			if conflict != "" {
+				result.Reason = conflict
+				if opts.OnConflict != models.ImportOnConflictGenerate {
+					result.Status = models.ImportStatusSkipped
+					continue
+				}
+				result.Status = models.ImportStatusRenamed
+				shortCode = ""
+			}
+		}
+
+		req := &models.CreateURLRequest{
+			URL:    record.URL,
+			Domain: &domain,
+			Tags:   record.Tags,
+		}
+		if shortCode != "" {
+			req.ShortCode = &shortCode
+		}
+		if record.Title != "" {
+			title := record.Title
+			if utf8.RuneCountInString(title) > maxImportTitleLength {
+				title = string([]rune(title)[:maxImportTitleLength])
+			}
+			req.Title = &title
+		}
+
+		prepared, err := s.prepareURL(req, &userID)
+		if err != nil {
+			result.Status = models.ImportStatusFailed
+			result.Reason = err.Error()
+			continue
+		}
+		if opts.ImportClicks {
+			prepared.url.ClickCount = record.Clicks
+		}
+		if record.CreatedAt != nil {
+			prepared.url.CreatedAt = *record.CreatedAt
+		}
+		seen[prepared.url.ShortCode] = true
+		pending = append(pending, prepared)
+		positions = append(positions, i)
+	}
+	if len(pending) == 0 {
+		return results
+	}
+
+	responses, errs := s.insertPrepared(pending, baseURL)
+	for i := range pending {
+		result := &results[positions[i]]
+		if errs[i] != nil {
+			result.Status = models.ImportStatusFailed
+			result.Reason = errs[i].Error()
+			continue
+		}
+		result.ShortCode = responses[i].ShortCode
+		result.ShortURL = responses[i].ShortURL
+	}
+
+	return results
+}
+
+// importCodeConflict returns why an original short code can't be kept, or "" when it is free to use
+func (s *urlService) importCodeConflict(domain, shortCode string, seen map[string]bool) (string, error) {
+	if err := s.validateCustomShortCode(shortCode); err != nil {
+		return err.Error(), nil
+	}
+	if seen[shortCode] {
+		return fmt.Sprintf("short code '%s' appears more than once in the import", shortCode), nil
+	}
+	available, err := s.checkShortCodeAvailability(domain, shortCode)
+	if err != nil {
+		return "", fmt.Errorf("failed to check short code availability: %w", err)
+	}
+	if !available {
+		return fmt.Sprintf("short code '%s' is already taken", shortCode), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+)
+
+// ErrInvalidCursor is returned when a listing's ?cursor= is malformed or was issued for another sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// defaultListLimit is the page size of link listings when ?limit= is not given
+const defaultListLimit = 50
+
+// listCursor is the decoded form of the opaque ?cursor= of link listings.
+// It records the sort order so a cursor can't be replayed against a different one.
+type listCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// urlFilter builds the repository filter of a listing query, checking that the folder belongs to the user
+func (s *urlService) urlFilter(userID string, query *models.URLListQuery) (repository.URLFilter, error) {
+	filter := repository.URLFilter{
+		Tag:           strings.TrimSpace(query.Tag),
+		Status:        query.Status,
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+		Search:        query.Search,
+		Campaign:      strings.TrimSpace(query.Campaign),
+	}
+	if query.Domain != nil {
+		domain := normalizeHostname(*query.Domain)
+		filter.Domain = &domain
+	}
+	if query.Folder != "" {
+		folder, err := s.folders.FindByID(userID, query.Folder)
+		if err != nil {
+			return filter, err
+		}
+		filter.FolderID = &folder.ID
+	}
+	return filter, nil
+}
+
+// newURLPage builds the repository page for the sorting and cursor of a listing query
+func newURLPage(query *models.URLListQuery) (repository.URLPage, error) {
+	page := repository.URLPage{
+		Sort:  query.Sort,
+		Desc:  query.Order != "asc",
+		Limit: query.Limit,
+	}
+	if page.Sort == "" {
+		page.Sort = repository.URLSortCreated
+	}
+	if page.Limit <= 0 {
+		page.Limit = defaultListLimit
+	}
+
+	if query.Cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(query.Cursor)
+		if err != nil {
+			return page, ErrInvalidCursor
+		}
+		var cursor listCursor
+		if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+			return page, ErrInvalidCursor
+		}
+		if cursor.Sort != page.Sort || cursor.Desc != page.Desc {
+			return page, fmt.Errorf("%w: issued for a different sort order", ErrInvalidCursor)
+		}
+		page.After = &repository.URLCursor{Value: cursor.Value, ID: cursor.ID}
+	}
+
+	return page, nil
+}
+
+// encodeListCursor returns the opaque cursor of the page that follows url
+func encodeListCursor(page repository.URLPage, url *entities.URL) string {
+	position := repository.NewURLCursor(page.Sort, url)
+	raw, _ := json.Marshal(listCursor{
+		Sort:  page.Sort,
+		Desc:  page.Desc,
+		Value: position.Value,
+		ID:    position.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"shortly-be/internal/entities"
+)
+
+// maxMetadataSize caps the encoded size of a link's free-form metadata
+const maxMetadataSize = 8 * 1024
+
+// maxTagsPerURL caps the number of tags on a single link
+const maxTagsPerURL = 20
+
+// optionalText trims a text field, returning nil for a missing or blank value
+func optionalText(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// normalizeTagNames trims tag names and drops blanks and case-insensitive duplicates, keeping the first spelling
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		if len(name) > 50 {
+			return nil, fmt.Errorf("tag '%s' is longer than 50 characters", name)
+		}
+		seen[strings.ToLower(name)] = true
+		normalized = append(normalized, name)
+	}
+	if len(normalized) > maxTagsPerURL {
+		return nil, fmt.Errorf("a link can have at most %d tags", maxTagsPerURL)
+	}
+	return normalized, nil
+}
+
+// validateMetadata checks that a link's metadata stays within maxMetadataSize once encoded
+func validateMetadata(metadata map[string]interface{}) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("invalid metadata: %w", err)
+	}
+	if len(encoded) > maxMetadataSize {
+		return fmt.Errorf("metadata must be at most %d bytes", maxMetadataSize)
+	}
+	return nil
+}
+
+// resolveFolder checks that a folder requested for a link belongs to the link's owner.
+// A missing or empty folder ID files the link at the top level.
+func (s *urlService) resolveFolder(folderID *string, userID *string) (*string, error) {
+	if folderID == nil || *folderID == "" {
+		return nil, nil
+	}
+	if userID == nil {
+		return nil, fmt.Errorf("folders require an account")
+	}
+	folder, err := s.folders.FindByID(*userID, *folderID)
+	if err != nil {
+		return nil, err
+	}
+	return &folder.ID, nil
+}
+
+// loadTags attaches their tags to the given links with a single query
+func (s *urlService) loadTags(urls []*entities.URL) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(urls))
+	for i, url := range urls {
+		ids[i] = url.ID
+	}
+
+	tags, err := s.tags.ListByURLIDs(ids)
+	if err != nil {
+		return err
+	}
+	for _, url := range urls {
+		url.Tags = tags[url.ID]
+		if url.Tags == nil {
+			url.Tags = []entities.Tag{}
+		}
+	}
+	return nil
+}
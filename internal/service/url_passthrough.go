@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	neturl "net/url"
+	"path"
+	"strings"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+)
+
+// isValidQueryPrecedence reports whether precedence is a supported query precedence
+func isValidQueryPrecedence(precedence string) bool {
+	return precedence == entities.QueryPrecedenceDestination || precedence == entities.QueryPrecedenceIncoming
+}
+
+// applyPassthrough appends the visitor's trailing path and merges their query parameters
+// into the destination, according to the link's forwarding options
+func applyPassthrough(destination string, entry *urlCacheEntry, req *models.RedirectRequest) (string, error) {
+	u, err := neturl.Parse(destination)
+	if err != nil {
+		return "", fmt.Errorf("invalid destination URL: %w", err)
+	}
+
+	if entry.ForwardPath && req.Path != "" && req.Path != "/" {
+		u.Path = joinForwardedPath(u.Path, req.Path)
+		u.RawPath = ""
+	}
+
+	if entry.ForwardQuery && req.RawQuery != "" {
+		incoming, err := neturl.ParseQuery(req.RawQuery)
+		if err != nil {
+			return "", fmt.Errorf("invalid query string: %w", err)
+		}
+		u.RawQuery = mergeQuery(u.RawQuery, incoming, entry.QueryPrecedence)
+	}
+
+	return u.String(), nil
+}
+
+// joinForwardedPath appends a forwarded path to the destination path.
+// The forwarded part is cleaned as an absolute path so ".." segments can't escape the destination's path.
+func joinForwardedPath(base, forwarded string) string {
+	cleaned := path.Clean("/" + forwarded)
+	if strings.HasSuffix(forwarded, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return strings.TrimSuffix(base, "/") + cleaned
+}
+
+// mergeQuery adds incoming parameters to the destination's raw query.
+// The destination's own parameters keep their order; on conflicts the side named by precedence wins.
+func mergeQuery(rawQuery string, incoming neturl.Values, precedence string) string {
+	existing, err := neturl.ParseQuery(rawQuery)
+	if err != nil {
+		// Leave an unparseable destination query untouched
+		return rawQuery
+	}
+
+	added := neturl.Values{}
+	for key, values := range incoming {
+		if _, conflict := existing[key]; conflict {
+			if precedence != entities.QueryPrecedenceIncoming {
+				continue
+			}
+			rawQuery = removeQueryParam(rawQuery, key)
+		}
+		added[key] = values
+	}
+
+	extra := added.Encode()
+	switch {
+	case extra == "":
+		return rawQuery
+	case rawQuery == "":
+		return extra
+	default:
+		return rawQuery + "&" + extra
+	}
+}
+
+// removeQueryParam drops every occurrence of key from a raw query string, keeping the rest as-is
+func removeQueryParam(rawQuery, key string) string {
+	parts := strings.Split(rawQuery, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		name, _, _ := strings.Cut(part, "=")
+		if decoded, err := neturl.QueryUnescape(name); err == nil && decoded == key {
+			continue
+		}
+		kept = append(kept, part)
+	}
+	return strings.Join(kept, "&")
+}
@@ -2,40 +2,110 @@ package service
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/base64"
+	"errors"
 	"fmt"
-	"regexp"
+	"log"
+	"net"
+	"net/http"
+	neturl "net/url"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
 
 	"shortly-be/internal/cache"
+	"shortly-be/internal/codefilter"
+	"shortly-be/internal/entities"
+	"shortly-be/internal/geoip"
+	"shortly-be/internal/importer"
 	"shortly-be/internal/models"
 	"shortly-be/internal/repository"
+	"shortly-be/internal/routing"
+	"shortly-be/internal/shortcode"
+	"shortly-be/internal/useragent"
 )
 
 // URLService defines the interface for URL business logic
 type URLService interface {
 	CreateShortURL(req *models.CreateURLRequest, userID *string, baseURL string) (*models.CreateURLResponse, error)
-	GetOriginalURL(shortCode string) (string, error)
-	GetURLStats(shortCode string, userID *string) (*models.URLStatsResponse, error)
-	GetClickAnalytics(shortCode string, userID *string, hours int) ([]map[string]interface{}, error)
-	DeleteURL(shortCode string, userID *string) error
-	UpdateExpiresAt(shortCode string, userID *string, expiresAt *time.Time) error
-	GetUserURLs(userID string) ([]*models.URLStatsResponse, error)
+	CreateShortURLs(rows []models.BulkCreateRow, userID *string, baseURL string) []models.BulkCreateResult
+	ImportURLs(records []importer.Record, userID string, opts *models.ImportOptions, baseURL string) ([]models.ImportResult, error)
+	GetOriginalURL(req *models.RedirectRequest) (*models.RedirectTarget, error)
+	VerifyURLPassword(host, shortCode, password string) (*models.URLUnlock, error)
+	GetURLStats(domain, shortCode string, userID *string) (*models.URLStatsResponse, error)
+	GetClickAnalytics(domain, shortCode string, userID *string, hours int, groupBy string) ([]map[string]interface{}, error)
+	GetVariantAnalytics(domain, shortCode string, userID *string, hours int) ([]map[string]interface{}, error)
+	DeleteURL(domain, shortCode string, userID *string) error
+	UpdateURL(domain, shortCode string, userID *string, req *models.UpdateURLRequest) error
+	GetURLRevisions(domain, shortCode string, userID *string, limit int) ([]entities.URLRevision, error)
+	RollbackURL(domain, shortCode string, userID *string, revisionID string) (*models.URLStatsResponse, error)
+	ArchiveURL(domain, shortCode string, userID string, archived bool) error
+	BatchUpdateURLs(userID string, req *models.BatchRequest) (*models.BatchResponse, error)
+	GetUserURLs(userID string, query *models.URLListQuery) (*models.URLListResponse, error)
+	GetUserClickAnalytics(userID string, query *models.URLListQuery, hours int) ([]map[string]interface{}, error)
+	GetCampaignAnalytics(userID string, query *models.URLListQuery, hours int) ([]map[string]interface{}, error)
+	ExportURLs(userID string, query *models.URLListQuery, fn func(url *entities.URL) error) error
+	ExportClicks(domain, shortCode string, userID *string, from, to *time.Time, fn func(click *entities.URLClick) error) error
+	BackfillDestinationHashes() (int, error)
+	BackfillCodeSkeletons() (int, error)
 }
 
 type urlService struct {
-	repo  repository.URLRepository
-	cache cache.Cache
-	ctx   context.Context
+	repo     repository.URLRepository
+	userRepo repository.UserRepository
+	ruleRepo repository.RoutingRuleRepository
+	destRepo repository.DestinationRepository
+	revRepo  repository.RevisionRepository
+	domains  repository.DomainRepository
+	tags     repository.TagRepository
+	folders  repository.FolderRepository
+	geo      geoip.Resolver
+	cache    cache.Cache
+	ctx      context.Context
+
+	utmPresets     repository.UTMPresetRepository
+	trackingParams []string // Query parameters stripped from destinations of users without their own list
+	codes          *shortcode.Generators
+	codeFilter     *codefilter.Filter // Reserved words and blocklist applied to custom and pooled codes
 }
 
 // NewURLService creates a new URL service
-func NewURLService(repo repository.URLRepository, cacheClient cache.Cache) URLService {
+func NewURLService(
+	repo repository.URLRepository,
+	userRepo repository.UserRepository,
+	ruleRepo repository.RoutingRuleRepository,
+	destRepo repository.DestinationRepository,
+	revRepo repository.RevisionRepository,
+	domainRepo repository.DomainRepository,
+	tagRepo repository.TagRepository,
+	folderRepo repository.FolderRepository,
+	utmPresetRepo repository.UTMPresetRepository,
+	trackingParams []string,
+	codeGenerators *shortcode.Generators,
+	codeFilter *codefilter.Filter,
+	geoResolver geoip.Resolver,
+	cacheClient cache.Cache,
+) URLService {
 	svc := &urlService{
-		repo: repo,
-		ctx:  context.Background(),
+		repo:     repo,
+		userRepo: userRepo,
+		ruleRepo: ruleRepo,
+		destRepo: destRepo,
+		revRepo:  revRepo,
+		domains:  domainRepo,
+		tags:     tagRepo,
+		folders:  folderRepo,
+		ctx:      context.Background(),
+
+		utmPresets:     utmPresetRepo,
+		trackingParams: trackingParams,
+		codes:          codeGenerators,
+		codeFilter:     codeFilter,
+	}
+	// Only set GeoIP resolver if provided (country rules never match without it)
+	if geoResolver != nil {
+		svc.geo = geoResolver
 	}
 	// Only set cache if provided (allows graceful degradation)
 	if cacheClient != nil {
@@ -44,75 +114,143 @@ func NewURLService(repo repository.URLRepository, cacheClient cache.Cache) URLSe
 	return svc
 }
 
-// Reserved short codes that cannot be used
-var reservedCodes = map[string]bool{
-	"admin":    true,
-	"api":      true,
-	"www":      true,
-	"mail":     true,
-	"ftp":      true,
-	"localhost": true,
-	"health":   true,
-	"auth":     true,
-	"login":    true,
-	"register": true,
-	"signin":   true,
-	"signup":   true,
-	"signout":  true,
-	"logout":   true,
-	"shorten":  true,
-	"urls":     true,
-	"url":      true,
-	"stats":    true,
-	"analytics": true,
-	"redirect": true,
-}
-
-// validateCustomShortCode validates a custom short code
+// ErrPasswordRequired is returned when a password-protected link is visited without unlocking it
+var ErrPasswordRequired = errors.New("password required")
+
+// ErrInvalidPassword is returned when the password entered for a protected link is wrong
+var ErrInvalidPassword = errors.New("invalid password")
+
+// ErrShortCodeTaken is returned when the requested short code belongs to another link
+var ErrShortCodeTaken = errors.New("short code is already taken")
+
+// shortCodeTaken returns ErrShortCodeTaken naming the code
+func shortCodeTaken(shortCode string) error {
+	return fmt.Errorf("%w: '%s'", ErrShortCodeTaken, shortCode)
+}
+
+// shortCodeReservationTTL bounds how long a crashed request can keep a code reserved
+const shortCodeReservationTTL = 30 * time.Second
+
+// hashURLPassword hashes a link password, returning nil when the password is empty
+func hashURLPassword(password *string) (*string, error) {
+	if password == nil || *password == "" {
+		return nil, nil
+	}
+	if len(*password) < 4 {
+		return nil, fmt.Errorf("password must be at least 4 characters long")
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	hash := string(hashedPassword)
+	return &hash, nil
+}
+
+// maxCustomCodeLength is the longest custom short code; urls.short_code leaves room for a few more characters
+const maxCustomCodeLength = 50
+
+// validateCustomShortCode validates a custom short code in NFC form (see codefilter.Normalize)
 func (s *urlService) validateCustomShortCode(shortCode string) error {
-	// Check length (min 3, max 20 characters)
-	if len(shortCode) < 3 {
+	// Check length (min 3, max 50 characters; emoji and other Unicode characters count once per code point)
+	length := utf8.RuneCountInString(shortCode)
+	if length < 3 {
 		return fmt.Errorf("short code must be at least 3 characters long")
 	}
-	if len(shortCode) > 20 {
-		return fmt.Errorf("short code must be at most 20 characters long")
+	if length > maxCustomCodeLength {
+		return fmt.Errorf("short code must be at most %d characters long", maxCustomCodeLength)
 	}
 
-	// Check format: only alphanumeric characters and hyphens/underscores
-	matched, err := regexp.MatchString("^[a-zA-Z0-9_-]+$", shortCode)
-	if err != nil {
-		return fmt.Errorf("failed to validate short code format: %w", err)
-	}
-	if !matched {
-		return fmt.Errorf("short code can only contain letters, numbers, hyphens, and underscores")
+	// Check format: alphanumeric characters and hyphens/underscores, plus Unicode letters and emoji when enabled
+	if err := s.codeFilter.CheckCharacters(shortCode); err != nil {
+		return err
 	}
 
-	// Check if it's a reserved word (case-insensitive)
-	if reservedCodes[strings.ToLower(shortCode)] {
+	// Check if it's a reserved word (case-insensitive) or contains a blocked word
+	switch err := s.codeFilter.Check(shortCode); {
+	case errors.Is(err, codefilter.ErrReserved):
 		return fmt.Errorf("short code '%s' is reserved and cannot be used", shortCode)
+	case err != nil:
+		return fmt.Errorf("short code '%s' is not allowed", shortCode)
 	}
 
 	return nil
 }
 
-// generateShortCode generates a random 8-character short code
-func (s *urlService) generateShortCode() (string, error) {
-	// Generate 6 bytes of random data
-	bytes := make([]byte, 6)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+// codeStrategy returns the strategy of a user's generated codes: the one they picked, or "" for the deployment default
+func (s *urlService) codeStrategy(userID *string) (string, error) {
+	if userID == nil || s.userRepo == nil {
+		return "", nil
+	}
+	user, err := s.userRepo.FindByID(*userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load user preferences: %w", err)
+	}
+	if user.CodeStrategy != nil {
+		return *user.CodeStrategy, nil
+	}
+	return "", nil
+}
+
+// resolveLinkDomain validates the custom domain requested for a new link.
+// The domain must be verified and owned by the user; nil or "" selects the default domain.
+func (s *urlService) resolveLinkDomain(requested *string, userID *string) (string, error) {
+	if requested == nil || *requested == "" {
+		return "", nil
+	}
+
+	hostname := normalizeHostname(*requested)
+	if userID == nil {
+		return "", fmt.Errorf("domain '%s' not found", hostname)
+	}
+	domain, err := s.domains.FindByUserHostname(*userID, hostname)
+	if err != nil {
+		return "", fmt.Errorf("domain '%s' not found", hostname)
+	}
+	if !domain.IsVerified() {
+		return "", fmt.Errorf("domain '%s' is not verified yet", hostname)
+	}
+	return domain.Hostname, nil
+}
+
+// resolveHostDomain maps the Host header of a visit to the domain its links live on.
+// Hosts that are not verified custom domains (BASE_URL, IPs, localhost) use the default domain.
+func (s *urlService) resolveHostDomain(host string) (string, error) {
+	hostname := normalizeHostname(host)
+	if hostname == "" || s.domains == nil {
+		return "", nil
+	}
+
+	verified, err := isVerifiedDomain(s.ctx, s.domains, s.cache, hostname)
+	if err != nil {
+		return "", err
 	}
+	if !verified {
+		return "", nil
+	}
+	return hostname, nil
+}
 
-	// Encode to base64 URL-safe string and take first 8 characters
-	encoded := base64.URLEncoding.EncodeToString(bytes)
-	return encoded[:8], nil
+// ShortURL builds the public URL of a link: baseURL for the default domain,
+// otherwise the custom domain with baseURL's scheme
+func ShortURL(baseURL, domain, shortCode string) string {
+	// Unicode codes are percent-encoded so the link is a valid URL everywhere (QR codes, plain-text emails)
+	shortCode = neturl.PathEscape(shortCode)
+	if domain == "" {
+		return fmt.Sprintf("%s/%s", baseURL, shortCode)
+	}
+	scheme := "https"
+	if strings.HasPrefix(baseURL, "http://") {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/%s", scheme, domain, shortCode)
 }
 
 // checkShortCodeAvailability checks if a short code is available using Redis cache first
-func (s *urlService) checkShortCodeAvailability(shortCode string) (bool, error) {
+func (s *urlService) checkShortCodeAvailability(domain, shortCode string) (bool, error) {
 	// Check Redis cache first (if available)
 	if s.cache != nil {
-		cacheKey := fmt.Sprintf("shortcode:exists:%s", shortCode)
+		cacheKey := shortCodeExistsKey(domain, shortCode)
 		exists, err := s.cache.Exists(s.ctx, cacheKey)
 		if err == nil && exists {
 			// Key exists in cache, check if it's marked as taken
@@ -124,258 +262,1089 @@ func (s *urlService) checkShortCodeAvailability(shortCode string) (bool, error)
 	}
 
 	// If not in cache or cache miss, check database
-	_, err := s.repo.FindByShortCode(shortCode)
+	_, err := s.repo.FindByShortCode(domain, shortCode)
 	if err != nil {
 		// If error is "URL not found", the code is available
-		if err.Error() == "URL not found or expired" {
-			// Cache that it's available (with short TTL to allow for race conditions)
-			if s.cache != nil {
-				cacheKey := fmt.Sprintf("shortcode:exists:%s", shortCode)
-				s.cache.Set(s.ctx, cacheKey, "available", 30*time.Second)
-			}
+		// Availability isn't cached: it can change any moment, and the insert has the final word anyway
+		if errors.Is(err, repository.ErrURLNotFound) {
 			return true, nil
 		}
+		// Scheduled links that are not active yet still own their code
+		if errors.Is(err, repository.ErrURLNotYetActive) {
+			return false, nil
+		}
 		// Other errors (like database errors) should be returned
 		return false, err
 	}
 	// If no error, URL exists, so code is not available
 	// Cache that it's taken (with longer TTL)
 	if s.cache != nil {
-		cacheKey := fmt.Sprintf("shortcode:exists:%s", shortCode)
+		cacheKey := shortCodeExistsKey(domain, shortCode)
 		s.cache.Set(s.ctx, cacheKey, "taken", 1*time.Hour)
 	}
 	return false, nil
 }
 
+// codeSkeletonBatchSize is the number of links read per query by BackfillCodeSkeletons
+const codeSkeletonBatchSize = 500
+
+// BackfillCodeSkeletons stores the skeleton of Unicode short codes created before skeletons were kept and
+// returns the number of links updated. A code that reads like another code on its domain keeps working
+// without a skeleton.
+func (s *urlService) BackfillCodeSkeletons() (int, error) {
+	updated := 0
+	afterID := "00000000-0000-0000-0000-000000000000"
+	for {
+		urls, err := s.repo.ListWithoutCodeSkeleton(afterID, codeSkeletonBatchSize)
+		if err != nil {
+			return updated, err
+		}
+		if len(urls) == 0 {
+			return updated, nil
+		}
+
+		for _, url := range urls {
+			afterID = url.ID
+			err := s.repo.SetCodeSkeleton(url.ID, url.ShortCode)
+			if errors.Is(err, repository.ErrShortCodeTaken) {
+				log.Printf("Warning: Short code '%s' reads like another code on its domain", url.ShortCode)
+				continue
+			}
+			if err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+}
+
+// reserveShortCode claims a custom short code for the duration of a request, so concurrent requests for
+// the same code fail fast instead of racing to the insert. The returned function releases the claim.
+// Without Redis (or when it fails) nothing is reserved and the insert alone decides.
+func (s *urlService) reserveShortCode(domain, shortCode string) (func(), error) {
+	release := func() {}
+	if s.cache == nil {
+		return release, nil
+	}
+
+	cacheKey := shortCodeReservationKey(domain, shortCode)
+	reserved, err := s.cache.SetNX(s.ctx, cacheKey, "reserved", shortCodeReservationTTL)
+	if err != nil {
+		log.Printf("ERROR: Failed to reserve short code '%s': %v", shortCode, err)
+		return release, nil
+	}
+	if !reserved {
+		return nil, shortCodeTaken(shortCode)
+	}
+	return func() { s.cache.Delete(s.ctx, cacheKey) }, nil
+}
+
 // CreateShortURL creates a new short URL
 func (s *urlService) CreateShortURL(req *models.CreateURLRequest, userID *string, baseURL string) (*models.CreateURLResponse, error) {
+	if req.ReuseExisting {
+		existing, err := s.findReusableURL(req, userID, baseURL)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+
+	pending, err := s.prepareURL(req, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Custom codes are reserved while the link is created; taken generated codes are replaced on insert
+	if !pending.generatedCode {
+		release, err := s.reserveShortCode(pending.url.Domain, pending.url.ShortCode)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	url, err := s.repo.Create(pending.url)
+	url, err = s.retryTakenCode(pending, url, err)
+	if err != nil {
+		return nil, s.insertError(pending.url, err)
+	}
+
+	return s.finishURL(url, pending, baseURL)
+}
+
+// newURL is a validated link that is ready to be inserted
+type newURL struct {
+	url           *entities.URL
+	destinations  []entities.URLDestination
+	tagNames      []string
+	generatedCode bool // The code was generated or pooled rather than requested
+}
+
+// prepareURL validates a creation request and picks its short code
+func (s *urlService) prepareURL(req *models.CreateURLRequest, userID *string) (*newURL, error) {
 	// Validate expiration time if provided
 	// Allow a 2-second buffer to account for network latency and processing time
 	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now().Add(-2*time.Second)) {
 		return nil, fmt.Errorf("expiration time cannot be in the past")
 	}
+	if err := validateActivationWindow(req.StartsAt, req.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	destination, err := s.linkDestination(req, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	domain, err := s.resolveLinkDomain(req.Domain, userID)
+	if err != nil {
+		return nil, err
+	}
 
 	var shortCode string
-	var err error
 
 	// If custom short code is provided, validate and use it
 	if req.ShortCode != nil && *req.ShortCode != "" {
-		customCode := strings.TrimSpace(*req.ShortCode)
-		
+		customCode := codefilter.Normalize(strings.TrimSpace(*req.ShortCode))
+
 		// Validate custom code
 		if err := s.validateCustomShortCode(customCode); err != nil {
 			return nil, err
 		}
 
 		// Check availability
-		available, err := s.checkShortCodeAvailability(customCode)
+		available, err := s.checkShortCodeAvailability(domain, customCode)
 		if err != nil {
 			return nil, fmt.Errorf("failed to check short code availability: %w", err)
 		}
 		if !available {
-			return nil, fmt.Errorf("short code '%s' is already taken", customCode)
+			return nil, shortCodeTaken(customCode)
 		}
 
 		shortCode = customCode
 	} else {
-		// Generate unique short code (retry if collision occurs)
-		maxAttempts := 10
-		for i := 0; i < maxAttempts; i++ {
-			shortCode, err = s.generateShortCode()
-			if err != nil {
-				return nil, err
-			}
+		shortCode, err = s.newShortCode(domain, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-			// Check if generated code is available (should be, but check anyway)
-			available, err := s.checkShortCodeAvailability(shortCode)
-			if err != nil {
-				return nil, fmt.Errorf("failed to check short code availability: %w", err)
-			}
-			if available {
+	redirectType, err := s.resolveRedirectType(req.RedirectType, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := hashURLPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	destinations, err := toDestinationEntities(req.Destinations)
+	if err != nil {
+		return nil, err
+	}
+	for i := range destinations {
+		// A new link can't reference existing destinations
+		destinations[i].ID = ""
+	}
+
+	deepLink, err := toDeepLinkEntity(req.DeepLink)
+	if err != nil {
+		return nil, err
+	}
+
+	tagNames, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(tagNames) > 0 && userID == nil {
+		return nil, fmt.Errorf("tags require an account")
+	}
+	if err := validateMetadata(req.Metadata); err != nil {
+		return nil, err
+	}
+	folderID, err := s.resolveFolder(req.FolderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	queryPrecedence := req.QueryPrecedence
+	if queryPrecedence == "" {
+		queryPrecedence = entities.QueryPrecedenceDestination
+	}
+
+	return &newURL{
+		url: &entities.URL{
+			Domain:         domain,
+			ShortCode:      shortCode,
+			OriginalURL:    destination,
+			UserID:         userID,
+			StartsAt:       req.StartsAt,
+			ExpiresAt:      req.ExpiresAt,
+			RedirectType:   redirectType,
+			PasswordHash:   passwordHash,
+			MaxClicks:      req.MaxClicks,
+			StickyVariants: req.StickyVariants,
+			DeepLink:       deepLink,
+
+			ForwardQuery:    req.ForwardQuery,
+			QueryPrecedence: queryPrecedence,
+			ForwardPath:     req.ForwardPath,
+
+			Title:    optionalText(req.Title),
+			Notes:    optionalText(req.Notes),
+			Metadata: req.Metadata,
+			FolderID: folderID,
+		},
+		destinations:  destinations,
+		tagNames:      tagNames,
+		generatedCode: req.ShortCode == nil || *req.ShortCode == "",
+	}, nil
+}
+
+// newShortCode picks the code of a link without a custom one
+func (s *urlService) newShortCode(domain string, userID *string) (string, error) {
+	strategy, err := s.codeStrategy(userID)
+	if err != nil {
+		return "", err
+	}
+
+	// Links on the default domain take a pre-generated code that no link used when it was pooled
+	if domain == "" {
+		for {
+			code, ok := s.codes.Pooled(strategy)
+			if !ok {
 				break
 			}
-
-			// If not available and we've exhausted attempts, return error
-			if i == maxAttempts-1 {
-				return nil, fmt.Errorf("failed to generate unique short code after %d attempts", maxAttempts)
+			// Words can be reserved after the code was pooled
+			if s.codeFilter.Accept(code) {
+				return code, nil
 			}
 		}
 	}
 
-	// Create the URL with the determined short code
-	url, err := s.repo.Create(shortCode, req.URL, userID, req.ExpiresAt)
-	if err != nil {
-		// Check if it's a unique constraint violation (shouldn't happen if we checked, but handle it)
-		if strings.Contains(err.Error(), "unique") || strings.Contains(err.Error(), "duplicate") {
-			// Mark as taken in cache
-			if s.cache != nil {
-				cacheKey := fmt.Sprintf("shortcode:exists:%s", shortCode)
-				s.cache.Set(s.ctx, cacheKey, "taken", 1*time.Hour)
-			}
-			return nil, fmt.Errorf("short code '%s' is already taken", shortCode)
+	// Without a pooled code, generate unique short code (retry if collision occurs; the generator widens its codes when retries pile up)
+	generator := s.codes.For(strategy)
+	maxAttempts := 10
+	for i := 0; i < maxAttempts; i++ {
+		candidate, err := generator.Generate(i)
+		if err != nil {
+			return "", err
+		}
+
+		// Check if generated code is available (the generators already skip reserved and blocked words)
+		available, err := s.checkShortCodeAvailability(domain, candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check short code availability: %w", err)
+		}
+		if available {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate unique short code after %d attempts", maxAttempts)
+}
+
+// maxInsertAttempts is the number of codes a link without a custom code tries before its creation fails
+const maxInsertAttempts = 3
+
+// retryTakenCode gives a link with a generated or pooled code a new one while the insert finds its code taken,
+// e.g. by a custom code claimed after the code was pooled or checked. url and err are the result of the first insert.
+func (s *urlService) retryTakenCode(pending *newURL, url *entities.URL, err error) (*entities.URL, error) {
+	for attempt := 1; pending.generatedCode && errors.Is(err, repository.ErrShortCodeTaken) && attempt < maxInsertAttempts; attempt++ {
+		pending.url.ShortCode, err = s.newShortCode(pending.url.Domain, pending.url.UserID)
+		if err != nil {
+			return nil, err
+		}
+		url, err = s.repo.Create(pending.url)
+	}
+	return url, err
+}
+
+// insertError converts a failed insert of a prepared link into the error returned to clients
+func (s *urlService) insertError(url *entities.URL, err error) error {
+	// Another link claimed the code between the availability check and the insert
+	if errors.Is(err, repository.ErrShortCodeTaken) {
+		// Mark as taken in cache
+		if s.cache != nil {
+			cacheKey := shortCodeExistsKey(url.Domain, url.ShortCode)
+			s.cache.Set(s.ctx, cacheKey, "taken", 1*time.Hour)
+		}
+		return shortCodeTaken(url.ShortCode)
+	}
+	return fmt.Errorf("failed to create URL: %w", err)
+}
+
+// finishURL saves the destinations and tags of an inserted link, caches it and builds the response
+func (s *urlService) finishURL(url *entities.URL, pending *newURL, baseURL string) (*models.CreateURLResponse, error) {
+	var err error
+	if len(pending.destinations) > 0 {
+		url.Destinations, err = s.destRepo.Replace(url.ID, pending.destinations)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save destinations: %w", err)
+		}
+	}
+
+	url.Tags = []entities.Tag{}
+	if len(pending.tagNames) > 0 {
+		url.Tags, err = s.tags.SetURLTags(url.ID, *url.UserID, pending.tagNames)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save tags: %w", err)
 		}
-		return nil, fmt.Errorf("failed to create URL: %w", err)
 	}
 
 	// Mark as taken in cache and cache the URL lookup
 	if s.cache != nil {
-		cacheKey := fmt.Sprintf("shortcode:exists:%s", shortCode)
+		cacheKey := shortCodeExistsKey(url.Domain, url.ShortCode)
 		s.cache.Set(s.ctx, cacheKey, "taken", 1*time.Hour)
+	}
+
+	// Cache the URL lookup (new links have no routing rules yet)
+	s.cacheURL(url, nil)
+
+	return newCreateURLResponse(url, baseURL), nil
+}
+
+// newCreateURLResponse converts a link, with its destinations and tags loaded, to the response of a creation
+func newCreateURLResponse(url *entities.URL, baseURL string) *models.CreateURLResponse {
+	return &models.CreateURLResponse{
+		Domain:            url.Domain,
+		ShortCode:         url.ShortCode,
+		OriginalURL:       url.OriginalURL,
+		ShortURL:          ShortURL(baseURL, url.Domain, url.ShortCode),
+		StartsAt:          url.StartsAt,
+		ExpiresAt:         url.ExpiresAt,
+		RedirectType:      url.RedirectType,
+		PasswordProtected: url.IsPasswordProtected(),
+		MaxClicks:         url.MaxClicks,
+		StickyVariants:    url.StickyVariants,
+		ForwardQuery:      url.ForwardQuery,
+		QueryPrecedence:   url.QueryPrecedence,
+		ForwardPath:       url.ForwardPath,
+		CreatedAt:         url.CreatedAt,
+		Destinations:      url.Destinations,
+		DeepLink:          url.DeepLink,
+		Title:             url.Title,
+		Notes:             url.Notes,
+		Metadata:          url.Metadata,
+		FolderID:          url.FolderID,
+		Tags:              url.Tags,
+	}
+}
 
-		// Cache the URL lookup
-		urlCacheKey := fmt.Sprintf("url:%s", shortCode)
-		urlCacheData := map[string]interface{}{
-			"original_url": url.OriginalURL,
-			"expires_at":   url.ExpiresAt,
+// resolveRedirectType returns the requested redirect status, the owner's default or 302 for anonymous links
+func (s *urlService) resolveRedirectType(requested *int, userID *string) (int, error) {
+	if requested != nil {
+		if !isValidRedirectType(*requested) {
+			return 0, fmt.Errorf("redirect type must be one of 301, 302, 307 or 308")
+		}
+		return *requested, nil
+	}
+	if userID != nil && s.userRepo != nil {
+		user, err := s.userRepo.FindByID(*userID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load user preferences: %w", err)
 		}
-		s.cache.SetJSON(s.ctx, urlCacheKey, urlCacheData, 1*time.Hour)
+		return user.DefaultRedirectType, nil
 	}
+	return http.StatusFound, nil
+}
 
-	// Success! Convert entity to response DTO
-	return &models.CreateURLResponse{
-		ShortCode:   url.ShortCode,
-		OriginalURL: url.OriginalURL,
-		ShortURL:    fmt.Sprintf("%s/%s", baseURL, url.ShortCode),
-		ExpiresAt:   url.ExpiresAt,
-		CreatedAt:   url.CreatedAt,
-	}, nil
+// temporaryRedirectType maps permanent redirect statuses to their temporary equivalent
+func temporaryRedirectType(status int) int {
+	switch status {
+	case http.StatusMovedPermanently:
+		return http.StatusFound
+	case http.StatusPermanentRedirect:
+		return http.StatusTemporaryRedirect
+	}
+	return status
 }
 
-// GetOriginalURL retrieves the original URL and increments click count
-func (s *urlService) GetOriginalURL(shortCode string) (string, error) {
-	// Try cache first (if available)
-	if s.cache != nil {
-		urlCacheKey := fmt.Sprintf("url:%s", shortCode)
-		var cachedData map[string]interface{}
-		err := s.cache.GetJSON(s.ctx, urlCacheKey, &cachedData)
-		if err == nil && cachedData != nil {
-			originalURL, ok := cachedData["original_url"].(string)
-			if ok && originalURL != "" {
-				// Check if expired
-				if expiresAtVal, exists := cachedData["expires_at"]; exists && expiresAtVal != nil {
-					if expiresAtStr, ok := expiresAtVal.(string); ok && expiresAtStr != "" {
-						expiresAt, err := time.Parse(time.RFC3339, expiresAtStr)
-						if err == nil && expiresAt.Before(time.Now()) {
-							// Expired, remove from cache and check DB
-							s.cache.Delete(s.ctx, urlCacheKey)
-						} else {
-							// Not expired, return cached URL
-							go func() {
-								if err := s.repo.IncrementClickCount(shortCode); err != nil {
-									fmt.Printf("Warning: failed to increment click count for %s: %v\n", shortCode, err)
-								}
-							}()
-							return originalURL, nil
-						}
-					}
-				} else {
-					// No expiration, return cached URL
-					go func() {
-						if err := s.repo.IncrementClickCount(shortCode); err != nil {
-							fmt.Printf("Warning: failed to increment click count for %s: %v\n", shortCode, err)
-						}
-					}()
-					return originalURL, nil
-				}
+// isValidRedirectType reports whether status is a supported redirect status code
+func isValidRedirectType(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// GetOriginalURL resolves the destination for a visit and increments click count.
+// Password-protected links return ErrPasswordRequired unless the request is unlocked.
+func (s *urlService) GetOriginalURL(req *models.RedirectRequest) (*models.RedirectTarget, error) {
+	shortCode := req.ShortCode
+	domain, err := s.resolveHostDomain(req.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, cached, err := s.lookupURL(domain, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trailing path segments only resolve on links that forward them
+	if !entry.ForwardPath && req.Path != "" && req.Path != "/" {
+		return nil, repository.ErrURLNotFound
+	}
+
+	// Scheduled links stay cached but don't redirect before their start time
+	if entry.StartsAt != nil && entry.StartsAt.After(time.Now()) {
+		return nil, repository.ErrURLNotYetActive
+	}
+
+	if entry.PasswordProtected && (req.Unlocked == nil || !req.Unlocked(&models.URLUnlock{URLID: entry.ID, PasswordVersion: entry.PasswordVersion})) {
+		return nil, ErrPasswordRequired
+	}
+
+	target := &models.RedirectTarget{
+		ShortCode:    shortCode,
+		Destination:  entry.OriginalURL,
+		RedirectType: entry.RedirectType,
+	}
+
+	// Routing rules are evaluated in order before falling back to the original URL
+	if len(entry.Rules) > 0 {
+		if rule := routing.Select(entry.Rules, s.newVisitor(req, entry.Rules)); rule != nil {
+			ruleID := rule.ID
+			target.Destination = rule.Destination
+			target.RuleID = &ruleID
+		}
+	}
+
+	// Visitors not targeted by a rule are split across the weighted A/B destinations
+	if target.RuleID == nil && len(entry.Destinations) > 0 {
+		stickyID := ""
+		if entry.StickyVariants {
+			stickyID = req.VariantID
+		}
+		variant := routing.PickDestination(entry.Destinations, stickyID)
+		variantID := variant.ID
+		target.Destination = variant.URL
+		target.VariantID = &variantID
+		target.Sticky = entry.StickyVariants
+	}
+
+	// Forward the visitor's trailing path and query parameters onto the chosen destination
+	if entry.ForwardPath || entry.ForwardQuery {
+		destination, err := applyPassthrough(target.Destination, entry, req)
+		if err != nil {
+			return nil, err
+		}
+		target.Destination = destination
+	}
+
+	// Mobile visitors are sent to the app first, falling back to the store or the web destination
+	if entry.DeepLink != nil {
+		target.AppURL, target.FallbackURL = deepLinkTargets(entry.DeepLink, req.UserAgent, target.Destination)
+	}
+
+	// Per-visitor destinations must not be cached by browsers, so permanent redirects become temporary
+	if len(entry.Rules) > 0 || len(entry.Destinations) > 0 {
+		target.RedirectType = temporaryRedirectType(target.RedirectType)
+	}
+
+	// Clicks on database hits and click-limited links are counted synchronously so
+	// clicks are logged reliably and cache hits can't exceed the limit
+	if !cached || entry.MaxClicks != nil {
+		if err := s.recordClick(domain, shortCode, target.VariantID, entry.MaxClicks != nil); err != nil {
+			return nil, err
+		}
+	} else {
+		go s.recordClick(domain, shortCode, target.VariantID, false)
+	}
+
+	return target, nil
+}
+
+// newVisitor builds the routing context for a request, resolving the country only when a rule needs it
+func (s *urlService) newVisitor(req *models.RedirectRequest, rules []entities.RoutingRule) *routing.Visitor {
+	country := ""
+	if s.geo != nil && routing.UsesCountry(rules) {
+		if ip := net.ParseIP(req.IP); ip != nil {
+			if code, err := s.geo.Country(ip); err == nil {
+				country = code
+			} else {
+				fmt.Printf("Warning: GeoIP lookup failed for %s: %v\n", req.IP, err)
 			}
 		}
 	}
+	return routing.NewVisitor(country, req.UserAgent, req.AcceptLanguage, time.Now())
+}
+
+// recordClick increments the click count for a short code.
+// For click-limited links a failed increment blocks the redirect; for other links it is only logged.
+func (s *urlService) recordClick(domain, shortCode string, variantID *string, limited bool) error {
+	err := s.repo.IncrementClickCount(domain, shortCode, variantID)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, repository.ErrClickLimitReached) {
+		// The limit was reached by this or a concurrent visit, stop serving the link from cache
+		s.invalidateURL(domain, shortCode)
+		return repository.ErrURLNotFound
+	}
 
-	// Cache miss or expired, get from database
-	url, err := s.repo.FindByShortCode(shortCode)
+	if limited {
+		return fmt.Errorf("failed to record click: %w", err)
+	}
+
+	// Log error but don't fail the redirect
+	fmt.Printf("Warning: failed to increment click count for %s: %v\n", shortCode, err)
+	return nil
+}
+
+// VerifyURLPassword checks the password of a password-protected link and returns what an unlock token
+// must carry, nil for links without a password
+func (s *urlService) VerifyURLPassword(host, shortCode, password string) (*models.URLUnlock, error) {
+	domain, err := s.resolveHostDomain(host)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Cache the result
-	if s.cache != nil {
-		urlCacheKey := fmt.Sprintf("url:%s", shortCode)
-		urlCacheData := map[string]interface{}{
-			"original_url": url.OriginalURL,
-			"expires_at":   url.ExpiresAt,
-		}
-		s.cache.SetJSON(s.ctx, urlCacheKey, urlCacheData, 1*time.Hour)
+	url, err := s.repo.FindByShortCode(domain, shortCode)
+	if err != nil {
+		return nil, err
 	}
 
-	// Increment click count synchronously
-	// This is a fast operation and ensures clicks are logged reliably
-	if err := s.repo.IncrementClickCount(shortCode); err != nil {
-		// Log error but don't fail the redirect
-		fmt.Printf("Warning: failed to increment click count for %s: %v\n", shortCode, err)
+	if !url.IsPasswordProtected() {
+		return nil, nil
 	}
 
-	return url.OriginalURL, nil
+	if err := bcrypt.CompareHashAndPassword([]byte(*url.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidPassword
+	}
+
+	return &models.URLUnlock{URLID: url.ID, PasswordVersion: url.PasswordVersion()}, nil
 }
 
 // GetURLStats retrieves statistics for a URL
-func (s *urlService) GetURLStats(shortCode string, userID *string) (*models.URLStatsResponse, error) {
-	url, err := s.repo.GetStats(shortCode, userID)
+func (s *urlService) GetURLStats(domain, shortCode string, userID *string) (*models.URLStatsResponse, error) {
+	url, err := s.repo.GetStats(domain, shortCode, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	url.Destinations, err = s.destRepo.ListByURLID(url.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.loadTags([]*entities.URL{url}); err != nil {
+		return nil, err
+	}
+
+	return newURLStatsResponse(url), nil
+}
+
+// newURLStatsResponse converts a URL entity to its statistics DTO
+func newURLStatsResponse(url *entities.URL) *models.URLStatsResponse {
 	return &models.URLStatsResponse{
-		ShortCode:   url.ShortCode,
-		OriginalURL: url.OriginalURL,
-		ClickCount:  url.ClickCount,
-		CreatedAt:   url.CreatedAt,
-		ExpiresAt:   url.ExpiresAt,
-	}, nil
+		Domain:            url.Domain,
+		ShortCode:         url.ShortCode,
+		OriginalURL:       url.OriginalURL,
+		ClickCount:        url.ClickCount,
+		CreatedAt:         url.CreatedAt,
+		StartsAt:          url.StartsAt,
+		ExpiresAt:         url.ExpiresAt,
+		RedirectType:      url.RedirectType,
+		PasswordProtected: url.IsPasswordProtected(),
+		MaxClicks:         url.MaxClicks,
+		StickyVariants:    url.StickyVariants,
+		ForwardQuery:      url.ForwardQuery,
+		QueryPrecedence:   url.QueryPrecedence,
+		ForwardPath:       url.ForwardPath,
+		Destinations:      url.Destinations,
+		DeepLink:          url.DeepLink,
+		DeletedAt:         url.DeletedAt,
+		ArchivedAt:        url.ArchivedAt,
+		Title:             url.Title,
+		Notes:             url.Notes,
+		Metadata:          url.Metadata,
+		FolderID:          url.FolderID,
+		Tags:              url.Tags,
+	}
 }
 
-// DeleteURL deletes a URL by short code
-func (s *urlService) DeleteURL(shortCode string, userID *string) error {
-	err := s.repo.Delete(shortCode, userID)
+// ArchiveURL archives or unarchives a URL owned by the user.
+// Archived links keep redirecting, so the cached redirect data stays valid.
+func (s *urlService) ArchiveURL(domain, shortCode string, userID string, archived bool) error {
+	return s.repo.SetArchived(domain, shortCode, userID, archived)
+}
+
+// DeleteURL moves a URL to the trash by short code
+func (s *urlService) DeleteURL(domain, shortCode string, userID *string) error {
+	err := s.repo.Delete(domain, shortCode, userID)
 	if err == nil && s.cache != nil {
 		// Invalidate cache
-		urlCacheKey := fmt.Sprintf("url:%s", shortCode)
-		s.cache.Delete(s.ctx, urlCacheKey)
-		cacheKey := fmt.Sprintf("shortcode:exists:%s", shortCode)
+		s.invalidateURL(domain, shortCode)
+		cacheKey := shortCodeExistsKey(domain, shortCode)
 		s.cache.Delete(s.ctx, cacheKey)
 	}
 	return err
 }
 
-// UpdateExpiresAt updates the expiration date for a URL
-func (s *urlService) UpdateExpiresAt(shortCode string, userID *string, expiresAt *time.Time) error {
-	// Validate expiration time if provided
-	// Allow a 2-second buffer to account for network latency and processing time
-	if expiresAt != nil && expiresAt.Before(time.Now().Add(-2*time.Second)) {
-		return fmt.Errorf("expiration time cannot be in the past")
+// UpdateURL applies the provided changes to a URL owned by the user
+func (s *urlService) UpdateURL(domain, shortCode string, userID *string, req *models.UpdateURLRequest) error {
+	url, err := s.repo.GetStats(domain, shortCode, userID)
+	if err != nil {
+		return err
+	}
+
+	if req.URL.Set {
+		if req.URL.Value == nil || !isValidDestinationURL(*req.URL.Value) {
+			return fmt.Errorf("url must be a valid absolute URL")
+		}
+		// New destinations are canonicalized and stripped of tracking parameters like those of new links
+		url.OriginalURL, err = s.canonicalDestination(*req.URL.Value, url.UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.StartsAt.Set {
+		startsAt, err := parseOptionalTime(req.StartsAt.Value)
+		if err != nil {
+			return err
+		}
+		url.StartsAt = startsAt
+	}
+
+	if req.ExpiresAt.Set {
+		expiresAt, err := parseOptionalTime(req.ExpiresAt.Value)
+		if err != nil {
+			return err
+		}
+
+		// Validate expiration time if provided
+		// Allow a 2-second buffer to account for network latency and processing time
+		if expiresAt != nil && expiresAt.Before(time.Now().Add(-2*time.Second)) {
+			return fmt.Errorf("expiration time cannot be in the past")
+		}
+		url.ExpiresAt = expiresAt
+	}
+
+	if err := validateActivationWindow(url.StartsAt, url.ExpiresAt); err != nil {
+		return err
+	}
+
+	if req.RedirectType.Set {
+		if req.RedirectType.Value == nil || !isValidRedirectType(*req.RedirectType.Value) {
+			return fmt.Errorf("redirect type must be one of 301, 302, 307 or 308")
+		}
+		url.RedirectType = *req.RedirectType.Value
+	}
+
+	if req.Password.Set {
+		passwordHash, err := hashURLPassword(req.Password.Value)
+		if err != nil {
+			return err
+		}
+		url.PasswordHash = passwordHash
+	}
+
+	if req.MaxClicks.Set {
+		if req.MaxClicks.Value != nil && *req.MaxClicks.Value < 1 {
+			return fmt.Errorf("max clicks must be at least 1")
+		}
+		url.MaxClicks = req.MaxClicks.Value
+	}
+
+	if req.DeepLink.Set {
+		url.DeepLink, err = toDeepLinkEntity(req.DeepLink.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.StickyVariants.Set {
+		url.StickyVariants = req.StickyVariants.Value != nil && *req.StickyVariants.Value
+	}
+
+	if req.ForwardQuery.Set {
+		url.ForwardQuery = req.ForwardQuery.Value != nil && *req.ForwardQuery.Value
+	}
+
+	if req.QueryPrecedence.Set {
+		url.QueryPrecedence = entities.QueryPrecedenceDestination
+		if req.QueryPrecedence.Value != nil && *req.QueryPrecedence.Value != "" {
+			if !isValidQueryPrecedence(*req.QueryPrecedence.Value) {
+				return fmt.Errorf("query precedence must be one of destination or incoming")
+			}
+			url.QueryPrecedence = *req.QueryPrecedence.Value
+		}
+	}
+
+	if req.ForwardPath.Set {
+		url.ForwardPath = req.ForwardPath.Value != nil && *req.ForwardPath.Value
+	}
+
+	if req.Title.Set {
+		url.Title = optionalText(req.Title.Value)
+	}
+
+	if req.Notes.Set {
+		url.Notes = optionalText(req.Notes.Value)
+	}
+
+	if req.Metadata.Set {
+		url.Metadata = nil
+		if req.Metadata.Value != nil {
+			if err := validateMetadata(*req.Metadata.Value); err != nil {
+				return err
+			}
+			url.Metadata = *req.Metadata.Value
+		}
+	}
+
+	if req.FolderID.Set {
+		url.FolderID, err = s.resolveFolder(req.FolderID.Value, url.UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	var tagNames []string
+	if req.Tags.Set && req.Tags.Value != nil {
+		tagNames, err = normalizeTagNames(*req.Tags.Value)
+		if err != nil {
+			return err
+		}
+	}
+	if len(tagNames) > 0 && url.UserID == nil {
+		return fmt.Errorf("tags require an account")
+	}
+
+	// nil leaves the destinations unchanged
+	var destinations *[]entities.URLDestination
+	if req.Destinations.Set {
+		replacement := []entities.URLDestination{}
+		if req.Destinations.Value != nil {
+			replacement, err = toDestinationEntities(*req.Destinations.Value)
+			if err != nil {
+				return err
+			}
+		}
+		destinations = &replacement
+	}
+
+	if err := s.repo.Update(url, userID, destinations); err != nil {
+		return err
+	}
+
+	if req.Tags.Set && url.UserID != nil {
+		if _, err := s.tags.SetURLTags(url.ID, *url.UserID, tagNames); err != nil {
+			return fmt.Errorf("failed to save tags: %w", err)
+		}
+	}
+
+	s.invalidateURL(domain, shortCode)
+	return nil
+}
+
+// GetURLRevisions retrieves the previous versions of a URL owned by the user, newest first
+func (s *urlService) GetURLRevisions(domain, shortCode string, userID *string, limit int) ([]entities.URLRevision, error) {
+	url, err := s.repo.GetStats(domain, shortCode, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.repo.UpdateExpiresAt(shortCode, userID, expiresAt)
+	return s.revRepo.ListByURLID(url.ID, limit)
 }
 
-// GetUserURLs retrieves all URLs for a specific user
-func (s *urlService) GetUserURLs(userID string) ([]*models.URLStatsResponse, error) {
-	urls, err := s.repo.GetByUserID(userID)
+// RollbackURL restores a URL owned by the user to a previous revision.
+// The version being replaced is itself saved as a revision, so a rollback can be undone.
+func (s *urlService) RollbackURL(domain, shortCode string, userID *string, revisionID string) (*models.URLStatsResponse, error) {
+	url, err := s.repo.GetStats(domain, shortCode, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	revision, err := s.revRepo.FindByID(url.ID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	url.OriginalURL = revision.OriginalURL
+	url.StartsAt = revision.StartsAt
+	url.ExpiresAt = revision.ExpiresAt
+	url.RedirectType = revision.RedirectType
+	url.PasswordHash = revision.PasswordHash
+	url.MaxClicks = revision.MaxClicks
+	url.StickyVariants = revision.StickyVariants
+	url.DeepLink = revision.DeepLink
+	url.ForwardQuery = revision.ForwardQuery
+	url.QueryPrecedence = revision.QueryPrecedence
+	url.ForwardPath = revision.ForwardPath
+	url.Title = revision.Title
+	url.Notes = revision.Notes
+	url.Metadata = revision.Metadata
+	url.FolderID = revision.FolderID
+
+	// Destinations deleted since the revision are recreated; surviving ones keep their click history
+	current, err := s.destRepo.ListByURLID(url.ID)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(current))
+	for _, destination := range current {
+		existing[destination.ID] = true
+	}
+	destinations := make([]entities.URLDestination, len(revision.Destinations))
+	for i, destination := range revision.Destinations {
+		if !existing[destination.ID] {
+			destination.ID = ""
+		}
+		destinations[i] = destination
+	}
+
+	if err := s.repo.Update(url, userID, &destinations); err != nil {
+		return nil, err
+	}
+
+	// Tags deleted since the revision are recreated
+	if url.UserID != nil {
+		url.Tags, err = s.tags.SetURLTags(url.ID, *url.UserID, revision.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore tags: %w", err)
+		}
+	}
+
+	s.invalidateURL(domain, shortCode)
+	return newURLStatsResponse(url), nil
+}
+
+// isValidDestinationURL reports whether raw is an absolute URL with a scheme and host
+func isValidDestinationURL(raw string) bool {
+	parsed, err := neturl.Parse(raw)
+	return err == nil && parsed.Scheme != "" && parsed.Host != ""
+}
+
+// parseOptionalTime parses an ISO 8601 timestamp into UTC; nil or "" yields nil
+func parseOptionalTime(value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format. Use ISO 8601 format (e.g., 2024-12-31T23:59:59Z)")
+	}
+	// Ensure the time is in UTC
+	utcTime := parsed.UTC()
+	return &utcTime, nil
+}
+
+// validateActivationWindow ensures a link's start time comes before its expiration
+func validateActivationWindow(startsAt, expiresAt *time.Time) error {
+	if startsAt != nil && expiresAt != nil && !startsAt.Before(*expiresAt) {
+		return fmt.Errorf("start time must be before expiration time")
+	}
+	return nil
+}
+
+// GetUserURLs retrieves a page of the active (or archived) URLs of a user matching the filters, excluding trashed ones
+func (s *urlService) GetUserURLs(userID string, query *models.URLListQuery) (*models.URLListResponse, error) {
+	filter, err := s.urlFilter(userID, query)
+	if err != nil {
+		return nil, err
+	}
+	archived := query.Archived
+	filter.Archived = &archived
+
+	page, err := newURLPage(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	limit := page.Limit
+	page.Limit++
+	urls, total, err := s.repo.GetByUserID(userID, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextCursor *string
+	if len(urls) > limit {
+		urls = urls[:limit]
+		cursor := encodeListCursor(page, urls[limit-1])
+		nextCursor = &cursor
+	}
+
+	if err := s.loadTags(urls); err != nil {
+		return nil, err
+	}
+
 	responses := make([]*models.URLStatsResponse, len(urls))
 	for i, url := range urls {
-		responses[i] = &models.URLStatsResponse{
-			ShortCode:   url.ShortCode,
-			OriginalURL: url.OriginalURL,
-			ClickCount:  url.ClickCount,
-			CreatedAt:   url.CreatedAt,
-			ExpiresAt:   url.ExpiresAt,
-		}
+		responses[i] = newURLStatsResponse(url)
 	}
 
-	return responses, nil
+	return &models.URLListResponse{
+		URLs:       responses,
+		NextCursor: nextCursor,
+		Total:      total,
+	}, nil
+}
+
+// GetUserClickAnalytics retrieves click analytics across the user's links (active and archived)
+// matching the listing filters
+func (s *urlService) GetUserClickAnalytics(userID string, query *models.URLListQuery, hours int) ([]map[string]interface{}, error) {
+	filter, err := s.urlFilter(userID, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserClickAnalytics(userID, filter, hours)
 }
 
-// GetClickAnalytics retrieves click analytics for a URL
-func (s *urlService) GetClickAnalytics(shortCode string, userID *string, hours int) ([]map[string]interface{}, error) {
+// GetCampaignAnalytics groups the user's links matching the filters by UTM campaign
+func (s *urlService) GetCampaignAnalytics(userID string, query *models.URLListQuery, hours int) ([]map[string]interface{}, error) {
+	filter, err := s.urlFilter(userID, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetCampaignAnalytics(userID, filter, hours)
+}
+
+// GetClickAnalytics retrieves click analytics for a URL, optionally grouped by A/B variant
+func (s *urlService) GetClickAnalytics(domain, shortCode string, userID *string, hours int, groupBy string) ([]map[string]interface{}, error) {
+	if groupBy != "" && groupBy != "variant" {
+		return nil, fmt.Errorf("group_by must be 'variant' when set")
+	}
+
 	// First verify the URL exists and user has access
-	url, err := s.repo.GetStats(shortCode, userID)
+	url, err := s.repo.GetStats(domain, shortCode, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get analytics
-	return s.repo.GetClickAnalytics(url.ID, hours)
+	return s.repo.GetClickAnalytics(url.ID, hours, groupBy == "variant")
+}
+
+// GetVariantAnalytics retrieves click totals per A/B destination of a URL
+func (s *urlService) GetVariantAnalytics(domain, shortCode string, userID *string, hours int) ([]map[string]interface{}, error) {
+	// First verify the URL exists and user has access
+	url, err := s.repo.GetStats(domain, shortCode, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetVariantClickTotals(url.ID, hours)
+}
+
+// deepLinkTargets returns the app URL and fallback for the visitor's platform, or empty strings
+// when the visitor is not on a mobile platform the link has an app URL for
+func deepLinkTargets(deepLink *entities.DeepLink, userAgent, webURL string) (string, string) {
+	info := useragent.Parse(userAgent)
+	if !info.IsMobile() {
+		return "", ""
+	}
+
+	appURL, storeURL := "", ""
+	switch info.OS {
+	case useragent.OSiOS:
+		appURL, storeURL = deepLink.IOSURL, deepLink.IOSStoreURL
+	case useragent.OSAndroid:
+		appURL, storeURL = deepLink.AndroidURL, deepLink.AndroidStoreURL
+	}
+	if appURL == "" {
+		return "", ""
+	}
+	if storeURL == "" {
+		storeURL = webURL
+	}
+	return appURL, storeURL
+}
+
+// toDeepLinkEntity validates a deep link configuration from a request
+func toDeepLinkEntity(req *models.DeepLinkRequest) (*entities.DeepLink, error) {
+	if req == nil {
+		return nil, nil
+	}
+	if req.IOSURL == "" && req.AndroidURL == "" {
+		return nil, fmt.Errorf("deep link needs an ios_url or android_url")
+	}
+	for _, appURL := range []string{req.IOSURL, req.AndroidURL} {
+		if appURL == "" {
+			continue
+		}
+		if err := validateAppURL(appURL); err != nil {
+			return nil, err
+		}
+	}
+	for _, storeURL := range []string{req.IOSStoreURL, req.AndroidStoreURL} {
+		if storeURL == "" {
+			continue
+		}
+		if err := validateStoreURL(storeURL); err != nil {
+			return nil, err
+		}
+	}
+
+	return &entities.DeepLink{
+		IOSURL:          req.IOSURL,
+		IOSStoreURL:     req.IOSStoreURL,
+		AndroidURL:      req.AndroidURL,
+		AndroidStoreURL: req.AndroidStoreURL,
+	}, nil
+}
+
+// validateAppURL accepts custom app schemes but rejects schemes that would run code in the interstitial page
+func validateAppURL(raw string) error {
+	parsed, err := neturl.Parse(raw)
+	if err != nil || parsed.Scheme == "" {
+		return fmt.Errorf("app URL '%s' must be an absolute URL such as myapp://path", raw)
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "javascript", "data", "vbscript", "file", "blob":
+		return fmt.Errorf("app URL scheme '%s' is not allowed", parsed.Scheme)
+	}
+	return nil
+}
+
+// validateStoreURL only accepts web URLs for store fallbacks, which the interstitial page navigates to
+func validateStoreURL(raw string) error {
+	parsed, err := neturl.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("store URL '%s' must be an absolute http or https URL", raw)
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		return nil
+	}
+	return fmt.Errorf("store URL '%s' must be an absolute http or https URL", raw)
+}
+
+// maxDestinations caps the number of A/B variants per link
+const maxDestinations = 20
+
+// toDestinationEntities validates A/B destinations from a request
+func toDestinationEntities(requests []models.DestinationRequest) ([]entities.URLDestination, error) {
+	if len(requests) > maxDestinations {
+		return nil, fmt.Errorf("a link can have at most %d destinations", maxDestinations)
+	}
+
+	destinations := make([]entities.URLDestination, len(requests))
+	for i, req := range requests {
+		// Checked here as well as by the request binding, which doesn't reach into PATCH's Optional values
+		if !isValidDestinationURL(req.URL) {
+			return nil, fmt.Errorf("destination URL '%s' must be a valid absolute URL", req.URL)
+		}
+		if req.Weight < 1 {
+			return nil, fmt.Errorf("destination weight must be at least 1")
+		}
+		if req.Label != nil && utf8.RuneCountInString(*req.Label) > 100 {
+			return nil, fmt.Errorf("destination label must be at most 100 characters")
+		}
+		destinations[i] = entities.URLDestination{
+			URL:    req.URL,
+			Weight: req.Weight,
+			Label:  req.Label,
+		}
+		if req.ID != nil {
+			destinations[i].ID = *req.ID
+		}
+	}
+	return destinations, nil
 }
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"shortly-be/internal/cache"
+	"shortly-be/internal/codefilter"
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+	"shortly-be/internal/shortcode"
+)
+
+// fakeURLRepository keeps links in memory. Only the methods link creation uses are implemented;
+// the embedded interface panics on the others.
+type fakeURLRepository struct {
+	repository.URLRepository
+
+	mu     sync.Mutex
+	urls   map[string]*entities.URL // By domain and short code
+	nextID int
+
+	// Lookups wait until this many have arrived, so concurrent requests all pass the availability
+	// check before any of them inserts
+	lookupBarrier int
+	arrived       int
+	allArrived    chan struct{}
+}
+
+func newFakeURLRepository(lookupBarrier int) *fakeURLRepository {
+	return &fakeURLRepository{
+		urls:          map[string]*entities.URL{},
+		lookupBarrier: lookupBarrier,
+		allArrived:    make(chan struct{}),
+	}
+}
+
+func (r *fakeURLRepository) FindByShortCode(domain, shortCode string) (*entities.URL, error) {
+	r.mu.Lock()
+	if r.arrived < r.lookupBarrier {
+		r.arrived++
+		if r.arrived == r.lookupBarrier {
+			close(r.allArrived)
+		}
+	}
+	url, ok := r.urls[domain+"/"+shortCode]
+	r.mu.Unlock()
+
+	if r.lookupBarrier > 0 {
+		<-r.allArrived
+	}
+	if !ok {
+		return nil, repository.ErrURLNotFound
+	}
+	found := *url
+	return &found, nil
+}
+
+// Create fails with ErrShortCodeTaken for a taken code, as the repository's Create does (see its tests)
+func (r *fakeURLRepository) Create(url *entities.URL) (*entities.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := url.Domain + "/" + url.ShortCode
+	if _, taken := r.urls[key]; taken {
+		return nil, repository.ErrShortCodeTaken
+	}
+	r.nextID++
+	created := *url
+	created.ID = fmt.Sprintf("url-%d", r.nextID)
+	created.CreatedAt = time.Now()
+	r.urls[key] = &created
+	found := created
+	return &found, nil
+}
+
+// fakeCache is an in-memory cache.Cache; expirations are ignored
+type fakeCache struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+var _ cache.Cache = (*fakeCache)(nil)
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{values: map[string]string{}}
+}
+
+func (c *fakeCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[key]
+	if !ok {
+		return "", fmt.Errorf("key not found")
+	}
+	return value, nil
+}
+
+func (c *fakeCache) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *fakeCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
+	return nil
+}
+
+func (c *fakeCache) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.values[key]
+	return ok, nil
+}
+
+func (c *fakeCache) SetNX(ctx context.Context, key string, value string, expiration time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	c.values[key] = value
+	return true, nil
+}
+
+func (c *fakeCache) SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.Set(ctx, key, string(data), expiration)
+}
+
+func (c *fakeCache) GetJSON(ctx context.Context, key string, dest interface{}) error {
+	value, err := c.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(value), dest)
+}
+
+// newTestURLService creates a URL service backed by repo and, unless it is nil, cacheClient
+func newTestURLService(t *testing.T, repo repository.URLRepository, cacheClient cache.Cache) URLService {
+	t.Helper()
+	generators, err := shortcode.NewGenerators(shortcode.Config{Length: 8}, nil)
+	if err != nil {
+		t.Fatalf("NewGenerators: %v", err)
+	}
+	return NewURLService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, generators, codefilter.New(nil, false), nil, cacheClient)
+}
+
+func TestCreateShortURLConcurrentCustomCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache bool // Without a cache the insert alone arbitrates
+	}{
+		{name: "reserved in cache", cache: true},
+		{name: "without cache", cache: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeURLRepository(2)
+			var cacheClient cache.Cache
+			if tt.cache {
+				cacheClient = newFakeCache()
+			}
+			svc := newTestURLService(t, repo, cacheClient)
+
+			userID := "user-1"
+			errs := make([]error, 2)
+			var wg sync.WaitGroup
+			for i := range errs {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					code := "launch"
+					_, errs[i] = svc.CreateShortURL(&models.CreateURLRequest{
+						URL:       fmt.Sprintf("https://example.com/%d", i),
+						ShortCode: &code,
+					}, &userID, "https://sho.rt")
+				}(i)
+			}
+			wg.Wait()
+
+			created, taken := 0, 0
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					created++
+				case errors.Is(err, ErrShortCodeTaken):
+					taken++
+				default:
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if created != 1 || taken != 1 {
+				t.Fatalf("created %d and rejected %d links, want 1 and 1", created, taken)
+			}
+			if len(repo.urls) != 1 {
+				t.Fatalf("repository holds %d links, want 1", len(repo.urls))
+			}
+		})
+	}
+}
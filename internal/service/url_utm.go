@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"shortly-be/internal/models"
+)
+
+// linkDestination returns the destination stored for a new link: the canonical form of the requested URL
+// with the UTM parameters of the request and its preset added
+func (s *urlService) linkDestination(req *models.CreateURLRequest, userID *string) (string, error) {
+	destination, err := s.canonicalDestination(req.URL, userID)
+	if err != nil {
+		return "", err
+	}
+	if req.UTM == nil && req.UTMPresetID == nil {
+		return destination, nil
+	}
+
+	var params []utmParam
+	if req.UTMPresetID != nil {
+		if userID == nil {
+			return "", fmt.Errorf("UTM presets require an account")
+		}
+		preset, err := s.utmPresets.FindByID(*userID, *req.UTMPresetID)
+		if err != nil {
+			return "", err
+		}
+		params = append(params, utmParams(&models.UTMParams{
+			Source:   preset.Source,
+			Medium:   preset.Medium,
+			Campaign: preset.Campaign,
+			Term:     preset.Term,
+			Content:  preset.Content,
+		})...)
+	}
+	if req.UTM != nil {
+		params = append(params, utmParams(req.UTM)...) // Later values win
+	}
+
+	return applyUTM(destination, params)
+}
+
+// utmParam is one UTM query parameter
+type utmParam struct{ name, value string }
+
+// utmParams lists the non-empty parameters of a UTM object
+func utmParams(utm *models.UTMParams) []utmParam {
+	var params []utmParam
+	for _, p := range []utmParam{
+		{"utm_source", utm.Source},
+		{"utm_medium", utm.Medium},
+		{"utm_campaign", utm.Campaign},
+		{"utm_term", utm.Term},
+		{"utm_content", utm.Content},
+	} {
+		if p.value = strings.TrimSpace(p.value); p.value != "" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// applyUTM sets UTM parameters on a canonical destination, replacing any it already has, and keeps the query sorted
+func applyUTM(destination string, params []utmParam) (string, error) {
+	if len(params) == 0 {
+		return destination, nil
+	}
+	parsed, err := neturl.Parse(destination)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL '%s'", destination)
+	}
+
+	values := make(map[string]string, len(params))
+	for _, p := range params {
+		values[p.name] = p.value
+	}
+
+	var pairs []string
+	for _, pair := range strings.Split(parsed.RawQuery, "&") {
+		name, _, _ := strings.Cut(pair, "=")
+		if _, replaced := values[name]; pair != "" && !replaced {
+			pairs = append(pairs, pair)
+		}
+	}
+	for name, value := range values {
+		pairs = append(pairs, name+"="+neturl.QueryEscape(value))
+	}
+
+	parsed.RawQuery = canonicalQuery(strings.Join(pairs, "&"), nil)
+	return parsed.String(), nil
+}
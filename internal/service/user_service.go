@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+	"shortly-be/internal/shortcode"
+)
+
+// UserService defines the interface for user account business logic
+type UserService interface {
+	GetPreferences(userID string) (*models.PreferencesResponse, error)
+	UpdatePreferences(userID string, req *models.UpdatePreferencesRequest) (*models.PreferencesResponse, error)
+}
+
+type userService struct {
+	userRepo repository.UserRepository
+}
+
+// NewUserService creates a new user service
+func NewUserService(userRepo repository.UserRepository) UserService {
+	return &userService{userRepo: userRepo}
+}
+
+// GetPreferences retrieves the link defaults for a user
+func (s *userService) GetPreferences(userID string) (*models.PreferencesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPreferencesResponse(user), nil
+}
+
+// UpdatePreferences applies the provided preference changes for a user
+func (s *userService) UpdatePreferences(userID string, req *models.UpdatePreferencesRequest) (*models.PreferencesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DefaultRedirectType != nil {
+		if !isValidRedirectType(*req.DefaultRedirectType) {
+			return nil, fmt.Errorf("redirect type must be one of 301, 302, 307 or 308")
+		}
+		user.DefaultRedirectType = *req.DefaultRedirectType
+	}
+	if req.TrackingParams.Set {
+		user.TrackingParams = nil
+		if req.TrackingParams.Value != nil {
+			user.TrackingParams, err = normalizeTrackingParams(*req.TrackingParams.Value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if req.StripFragments != nil {
+		user.StripFragments = *req.StripFragments
+	}
+	if req.CodeStrategy.Set {
+		if req.CodeStrategy.Value != nil && !shortcode.IsStrategy(*req.CodeStrategy.Value) {
+			return nil, fmt.Errorf("code strategy must be one of %s", strings.Join(shortcode.Strategies, ", "))
+		}
+		user.CodeStrategy = req.CodeStrategy.Value
+	}
+
+	if err := s.userRepo.UpdatePreferences(user); err != nil {
+		return nil, err
+	}
+
+	return newPreferencesResponse(user), nil
+}
+
+// maxTrackingParams caps the number of tracking parameter patterns of a user
+const maxTrackingParams = 100
+
+// normalizeTrackingParams trims and lowercases tracking parameter patterns, dropping duplicates.
+// The result is never nil, so an empty list is stored as "strip nothing" rather than "use the default".
+func normalizeTrackingParams(params []string) ([]string, error) {
+	if len(params) > maxTrackingParams {
+		return nil, fmt.Errorf("at most %d tracking parameters are allowed", maxTrackingParams)
+	}
+	normalized := make([]string, 0, len(params))
+	seen := make(map[string]bool, len(params))
+	for _, param := range params {
+		param = strings.ToLower(strings.TrimSpace(param))
+		if param == "" || param == "*" || len(param) > 100 {
+			return nil, fmt.Errorf("invalid tracking parameter '%s'", param)
+		}
+		if !seen[param] {
+			seen[param] = true
+			normalized = append(normalized, param)
+		}
+	}
+	return normalized, nil
+}
+
+// newPreferencesResponse builds the preferences response of a user
+func newPreferencesResponse(user *entities.User) *models.PreferencesResponse {
+	return &models.PreferencesResponse{
+		DefaultRedirectType: user.DefaultRedirectType,
+		TrackingParams:      user.TrackingParams,
+		StripFragments:      user.StripFragments,
+		CodeStrategy:        user.CodeStrategy,
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"shortly-be/internal/entities"
+	"shortly-be/internal/models"
+	"shortly-be/internal/repository"
+)
+
+// UTMPresetService defines the interface for managing the UTM presets users apply to new links
+type UTMPresetService interface {
+	ListPresets(userID string) ([]entities.UTMPreset, error)
+	CreatePreset(userID string, req *models.UTMPresetRequest) (*entities.UTMPreset, error)
+	UpdatePreset(userID, presetID string, req *models.UpdateUTMPresetRequest) (*entities.UTMPreset, error)
+	DeletePreset(userID, presetID string) error
+}
+
+type utmPresetService struct {
+	presetRepo repository.UTMPresetRepository
+}
+
+// NewUTMPresetService creates a new UTM preset service
+func NewUTMPresetService(presetRepo repository.UTMPresetRepository) UTMPresetService {
+	return &utmPresetService{
+		presetRepo: presetRepo,
+	}
+}
+
+// ListPresets retrieves the UTM presets of the user
+func (s *utmPresetService) ListPresets(userID string) ([]entities.UTMPreset, error) {
+	return s.presetRepo.ListByUserID(userID)
+}
+
+// CreatePreset saves a set of UTM parameters under a name
+func (s *utmPresetService) CreatePreset(userID string, req *models.UTMPresetRequest) (*entities.UTMPreset, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("preset name is required")
+	}
+
+	return s.presetRepo.Create(&entities.UTMPreset{
+		UserID:   userID,
+		Name:     name,
+		Source:   strings.TrimSpace(req.Source),
+		Medium:   strings.TrimSpace(req.Medium),
+		Campaign: strings.TrimSpace(req.Campaign),
+		Term:     strings.TrimSpace(req.Term),
+		Content:  strings.TrimSpace(req.Content),
+	})
+}
+
+// UpdatePreset renames a UTM preset of the user or changes its parameters
+func (s *utmPresetService) UpdatePreset(userID, presetID string, req *models.UpdateUTMPresetRequest) (*entities.UTMPreset, error) {
+	preset, err := s.presetRepo.FindByID(userID, presetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		preset.Name = strings.TrimSpace(*req.Name)
+		if preset.Name == "" {
+			return nil, fmt.Errorf("preset name is required")
+		}
+	}
+	for _, field := range []struct {
+		value  *string
+		target *string
+	}{
+		{req.Source, &preset.Source},
+		{req.Medium, &preset.Medium},
+		{req.Campaign, &preset.Campaign},
+		{req.Term, &preset.Term},
+		{req.Content, &preset.Content},
+	} {
+		if field.value != nil {
+			*field.target = strings.TrimSpace(*field.value)
+		}
+	}
+
+	return s.presetRepo.Update(preset)
+}
+
+// DeletePreset removes a UTM preset of the user
+func (s *utmPresetService) DeletePreset(userID, presetID string) error {
+	return s.presetRepo.Delete(userID, presetID)
+}
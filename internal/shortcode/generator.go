@@ -0,0 +1,205 @@
+// Package shortcode generates the short codes of new links with configurable strategies
+package shortcode
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Generation strategies
+const (
+	StrategyRandom   = "random"   // Random characters of the configured alphabet
+	StrategySequence = "sequence" // Base62 encoding of a Postgres sequence value
+	StrategyHashids  = "hashids"  // Sequence values obfuscated with a salted alphabet
+	StrategyWords    = "words"    // Human-readable word combinations such as "brave-otter"
+)
+
+// Strategies lists the valid strategies
+var Strategies = []string{StrategyRandom, StrategySequence, StrategyHashids, StrategyWords}
+
+// IsStrategy reports whether s names a generation strategy
+func IsStrategy(s string) bool {
+	for _, strategy := range Strategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxLength is the longest code a generator produces, matching urls.short_code
+const MaxLength = 32
+
+// DefaultAlphabet leaves out characters that are easily confused (0/O, 1/l/I) and the URL punctuation - and _
+const DefaultAlphabet = "23456789abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// base62Alphabet is used by the sequence-based strategies, whose codes can't collide with each other
+const base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Generator produces candidate short codes for new links
+type Generator interface {
+	// Generate returns a candidate code. attempt counts the candidates of the same link that were already
+	// taken (0 for the first); generators use it to widen their codes as the keyspace fills up.
+	Generate(attempt int) (string, error)
+}
+
+// Sequence hands out increasing unique values, e.g. from a Postgres sequence
+type Sequence interface {
+	Next() (int64, error)
+}
+
+// SequenceFunc adapts a function to the Sequence interface
+type SequenceFunc func() (int64, error)
+
+// Next calls f
+func (f SequenceFunc) Next() (int64, error) {
+	return f()
+}
+
+// Config selects the deployment's default strategy and tunes the generators
+type Config struct {
+	Strategy string // Default strategy, StrategyRandom when empty
+	Length   int    // Minimum code length of the random, sequence and hashids strategies
+	Alphabet string // Characters of random codes, DefaultAlphabet when empty
+	Salt     string // Secret that makes hashids codes unpredictable
+}
+
+// Generators holds one generator per strategy, so links of users who picked different strategies share their state
+type Generators struct {
+	defaultStrategy string
+	byStrategy      map[string]Generator
+	pool            *Pool
+	accept          func(code string) bool
+}
+
+// NewGenerators validates the configuration and creates a generator for every strategy
+func NewGenerators(cfg Config, seq Sequence) (*Generators, error) {
+	if cfg.Strategy == "" {
+		cfg.Strategy = StrategyRandom
+	}
+	if !IsStrategy(cfg.Strategy) {
+		return nil, fmt.Errorf("unknown short code strategy '%s', expected one of %s", cfg.Strategy, strings.Join(Strategies, ", "))
+	}
+	if cfg.Length < 4 || cfg.Length > MaxLength {
+		return nil, fmt.Errorf("short code length must be between 4 and %d", MaxLength)
+	}
+	if cfg.Alphabet == "" {
+		cfg.Alphabet = DefaultAlphabet
+	}
+	if err := validateAlphabet(cfg.Alphabet); err != nil {
+		return nil, err
+	}
+
+	return &Generators{
+		defaultStrategy: cfg.Strategy,
+		byStrategy: map[string]Generator{
+			StrategyRandom:   newRandomGenerator(cfg.Alphabet, cfg.Length),
+			StrategySequence: newSequenceGenerator(seq, cfg.Length),
+			StrategyHashids:  newHashidsGenerator(seq, cfg.Length, cfg.Salt),
+			StrategyWords:    newWordsGenerator(),
+		},
+	}, nil
+}
+
+// For returns the generator of a strategy; "" or an unknown strategy selects the deployment's default
+func (g *Generators) For(strategy string) Generator {
+	generator, ok := g.byStrategy[strategy]
+	if !ok {
+		generator = g.byStrategy[g.defaultStrategy]
+	}
+	if g.accept != nil {
+		return &filteredGenerator{Generator: generator, accept: g.accept}
+	}
+	return generator
+}
+
+// Default returns the generator of the deployment's default strategy
+func (g *Generators) Default() Generator {
+	return g.For(g.defaultStrategy)
+}
+
+// UseFilter makes the generators skip codes that accept rejects, such as reserved or offensive words.
+// It must be called before the generators are used.
+func (g *Generators) UseFilter(accept func(code string) bool) {
+	g.accept = accept
+}
+
+// UsePool makes links of the default strategy take their codes from a pool of pre-generated codes
+func (g *Generators) UsePool(pool *Pool) {
+	g.pool = pool
+}
+
+// Pooled returns an unused code from the pool when the strategy is the deployment's default.
+// ok is false without a pool, for other strategies and when the pool is empty.
+func (g *Generators) Pooled(strategy string) (code string, ok bool) {
+	if g.pool == nil {
+		return "", false
+	}
+	if _, known := g.byStrategy[strategy]; known && strategy != g.defaultStrategy {
+		return "", false
+	}
+	return g.pool.Take()
+}
+
+// maxRejections is the number of rejected codes in a row after which a filtered generator gives up
+const maxRejections = 100
+
+// filteredGenerator skips the codes of a generator that a filter rejects
+type filteredGenerator struct {
+	Generator
+	accept func(code string) bool
+}
+
+// Generate returns the first accepted code; rejected ones don't count as collisions
+func (f *filteredGenerator) Generate(attempt int) (string, error) {
+	for i := 0; i < maxRejections; i++ {
+		code, err := f.Generator.Generate(attempt)
+		if err != nil || f.accept(code) {
+			return code, err
+		}
+		attempt = 0
+	}
+	return "", fmt.Errorf("generated short codes were rejected %d times in a row", maxRejections)
+}
+
+// validateAlphabet ensures an alphabet only has URL-safe code characters, each once, and enough of them
+func validateAlphabet(alphabet string) error {
+	seen := make(map[rune]bool, len(alphabet))
+	for _, r := range alphabet {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("short code alphabet may only contain letters, digits, '-' and '_'")
+		}
+		if seen[r] {
+			return fmt.Errorf("short code alphabet contains '%c' more than once", r)
+		}
+		seen[r] = true
+	}
+	if len(seen) < 16 {
+		return fmt.Errorf("short code alphabet needs at least 16 characters")
+	}
+	return nil
+}
+
+// growAfterCollisions is the number of taken candidates in a row after which a generator widens its codes
+const growAfterCollisions = 2
+
+// growth widens the codes of a generator when candidates keep colliding, a sign that the keyspace
+// at the current size is filling up. It only lives in memory and is relearned after a restart.
+type growth struct {
+	extra atomic.Int32 // Added to the base size
+	max   int32
+}
+
+// observe records the attempt of a generation and returns the current extra size
+func (g *growth) observe(attempt int) int {
+	if attempt == growAfterCollisions {
+		for {
+			extra := g.extra.Load()
+			if extra >= g.max || g.extra.CompareAndSwap(extra, extra+1) {
+				break
+			}
+		}
+	}
+	return int(g.extra.Load())
+}
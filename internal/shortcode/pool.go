@@ -0,0 +1,216 @@
+package shortcode
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// refillChunk is the number of codes generated and stored per round trip while refilling the pool
+const refillChunk = 1000
+
+// PoolStore keeps the pool of pre-generated codes shared by all instances
+type PoolStore interface {
+	// Lease removes up to n codes from the pool and returns them; no other caller receives them
+	Lease(n int) ([]string, error)
+	// Add stores codes that no link uses yet, without letting the pool grow beyond size, and returns how many
+	// were added. It must be safe to call from several instances at once.
+	Add(codes []string, size int) (int, error)
+	// Count returns the number of pooled codes
+	Count() (int, error)
+}
+
+// PoolConfig sizes the pool
+type PoolConfig struct {
+	Size      int // Codes the refiller keeps in the shared pool
+	LeaseSize int // Codes an instance leases into memory at a time
+}
+
+// PoolStats are the pool's metrics
+type PoolStats struct {
+	Size      int   // Target depth of the shared pool
+	Depth     int64 // Codes in the shared pool at the last count or lease
+	Leased    int   // Codes this instance holds in memory
+	Served    int64 // Codes handed to new links by this instance
+	Misses    int64 // Times the pool was empty and a link fell back to generating and checking a code
+	Generated int64 // Codes this instance added to the shared pool
+}
+
+// Pool hands out pre-generated codes that no link uses yet, so links can be created without checking
+// their code. Links claiming a pooled code as a custom one remove it from the shared pool; a code already
+// leased into memory may still be taken that way, so callers retry with another code when the insert fails.
+// A background refiller keeps the shared pool topped up with codes of the deployment's default generator;
+// each instance leases batches of them into memory. Leased codes that are never used (e.g. on a restart)
+// are simply lost to the keyspace.
+type Pool struct {
+	store     PoolStore
+	generator Generator
+	size      int
+	leaseSize int
+
+	mu     sync.Mutex
+	local  []string
+	refill chan struct{}
+
+	depth     atomic.Int64
+	served    atomic.Int64
+	misses    atomic.Int64
+	generated atomic.Int64
+}
+
+// NewPool creates a pool filled from generator
+func NewPool(store PoolStore, generator Generator, cfg PoolConfig) (*Pool, error) {
+	if cfg.Size <= 0 {
+		return nil, fmt.Errorf("short code pool size must be positive")
+	}
+	if cfg.LeaseSize <= 0 || cfg.LeaseSize > cfg.Size {
+		return nil, fmt.Errorf("short code pool lease size must be between 1 and the pool size")
+	}
+	return &Pool{
+		store:     store,
+		generator: generator,
+		size:      cfg.Size,
+		leaseSize: cfg.LeaseSize,
+		refill:    make(chan struct{}, 1),
+	}, nil
+}
+
+// Take returns an unused code, leasing a new batch when the in-memory one is used up.
+// ok is false when the pool is empty or can't be reached; callers then generate and check a code themselves.
+func (p *Pool) Take() (code string, ok bool) {
+	if code, ok := p.pop(); ok {
+		return code, true
+	}
+
+	// The lease runs without the lock so other callers aren't held up by the round trip; callers that find
+	// the batch used up at the same time each lease one and the surplus is kept for later
+	codes, err := p.store.Lease(p.leaseSize)
+	if err != nil {
+		log.Printf("ERROR: Failed to lease short codes from the pool: %v", err)
+	}
+	if depth := p.depth.Add(-int64(len(codes))); depth < 0 {
+		p.depth.Store(0)
+	}
+	// Top up the shared pool without waiting for the next interval
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+
+	p.mu.Lock()
+	p.local = append(p.local, codes...)
+	p.mu.Unlock()
+
+	if code, ok := p.pop(); ok {
+		return code, true
+	}
+	p.misses.Add(1)
+	return "", false
+}
+
+// pop removes a code from the in-memory batch
+func (p *Pool) pop() (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.local) == 0 {
+		return "", false
+	}
+	code := p.local[len(p.local)-1]
+	p.local = p.local[:len(p.local)-1]
+	p.served.Add(1)
+	return code, true
+}
+
+// Refill tops the shared pool up to its size and returns the number of codes added. The missing codes are
+// recounted before every chunk, since other instances refill and lease the same pool.
+func (p *Pool) Refill() (int, error) {
+	added, attempt, stalls := 0, 0, 0
+	for {
+		depth, err := p.store.Count()
+		if err != nil {
+			return added, err
+		}
+		p.depth.Store(int64(depth))
+		if depth >= p.size {
+			return added, nil
+		}
+
+		n := p.size - depth
+		if n > refillChunk {
+			n = refillChunk
+		}
+
+		codes := make([]string, n)
+		for i := range codes {
+			// Only the first code reports collisions, so the generator widens its codes at most once per chunk
+			codes[i], err = p.generator.Generate(attempt)
+			if err != nil {
+				return added, err
+			}
+			attempt = 0
+		}
+
+		count, err := p.store.Add(codes, p.size)
+		if err != nil {
+			return added, err
+		}
+		added += count
+		p.depth.Store(int64(depth + count))
+		p.generated.Add(int64(count))
+
+		// Mostly taken codes mean the keyspace at the current length is filling up
+		if count*2 < len(codes) {
+			attempt = growAfterCollisions
+		}
+		if count == 0 {
+			stalls++
+			if stalls == 3 {
+				return added, fmt.Errorf("generated short codes are all taken")
+			}
+		} else {
+			stalls = 0
+		}
+	}
+}
+
+// Run refills the pool every interval and whenever an instance leases a batch; it never returns
+// and is meant to run in its own goroutine
+func (p *Pool) Run(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		added, err := p.Refill()
+		if err != nil {
+			log.Printf("ERROR: Failed to refill short code pool: %v", err)
+		} else if added > 0 {
+			log.Printf("Added %d short codes to the pool", added)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-p.refill:
+		}
+	}
+}
+
+// Stats returns the pool's metrics
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	leased := len(p.local)
+	p.mu.Unlock()
+
+	return PoolStats{
+		Size:      p.size,
+		Depth:     p.depth.Load(),
+		Leased:    leased,
+		Served:    p.served.Load(),
+		Misses:    p.misses.Load(),
+		Generated: p.generated.Load(),
+	}
+}
@@ -0,0 +1,40 @@
+package shortcode
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// randomGenerator picks every character of a code uniformly from an alphabet
+type randomGenerator struct {
+	alphabet []byte
+	length   int
+	growth   growth
+}
+
+func newRandomGenerator(alphabet string, length int) *randomGenerator {
+	g := &randomGenerator{alphabet: []byte(alphabet), length: length}
+	g.growth.max = int32(MaxLength - length)
+	return g
+}
+
+// Generate returns length random characters, one more for every time the keyspace was found to be filling up
+func (g *randomGenerator) Generate(attempt int) (string, error) {
+	length := g.length + g.growth.observe(attempt)
+
+	// Reject bytes above the largest multiple of the alphabet size so every character is equally likely
+	limit := 256 - 256%len(g.alphabet)
+	code := make([]byte, 0, length)
+	buf := make([]byte, length*2)
+	for len(code) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate random bytes: %w", err)
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(code) < length {
+				code = append(code, g.alphabet[int(b)%len(g.alphabet)])
+			}
+		}
+	}
+	return string(code), nil
+}
@@ -0,0 +1,131 @@
+package shortcode
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// maxSequenceLength caps the minimum length of sequence codes so that the offset fits in an int64
+const maxSequenceLength = 10
+
+// sequenceOffset returns the smallest value whose base62 encoding has the given number of characters
+func sequenceOffset(length int) int64 {
+	if length > maxSequenceLength {
+		length = maxSequenceLength
+	}
+	offset := int64(1)
+	for i := 1; i < length; i++ {
+		offset *= int64(len(base62Alphabet))
+	}
+	return offset
+}
+
+// encodeBase encodes a non-negative number with the characters of alphabet as digits
+func encodeBase(n int64, alphabet string) string {
+	base := int64(len(alphabet))
+	if n == 0 {
+		return alphabet[:1]
+	}
+	var digits []byte
+	for ; n > 0; n /= base {
+		digits = append(digits, alphabet[n%base])
+	}
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+	return string(digits)
+}
+
+// sequenceGenerator encodes consecutive sequence values in base62. Its codes never collide with each other,
+// so a taken candidate (a custom code) is simply skipped, and codes grow on their own as the sequence advances.
+type sequenceGenerator struct {
+	seq    Sequence
+	offset int64
+}
+
+func newSequenceGenerator(seq Sequence, length int) *sequenceGenerator {
+	return &sequenceGenerator{seq: seq, offset: sequenceOffset(length)}
+}
+
+// Generate encodes the next sequence value
+func (g *sequenceGenerator) Generate(attempt int) (string, error) {
+	if g.seq == nil {
+		return "", fmt.Errorf("no sequence configured for short codes")
+	}
+	value, err := g.seq.Next()
+	if err != nil {
+		return "", err
+	}
+	return encodeBase(g.offset+value, base62Alphabet), nil
+}
+
+// hashidsGenerator obfuscates sequence values like Hashids: the base62 alphabet is shuffled with a salt,
+// and each code starts with a "lottery" character that selects a further shuffle for the rest of the code.
+// Codes stay unique and reversible but consecutive links don't get consecutive codes.
+type hashidsGenerator struct {
+	seq      Sequence
+	offset   int64
+	alphabet string
+	salt     string
+}
+
+func newHashidsGenerator(seq Sequence, length int, salt string) *hashidsGenerator {
+	// The lottery character takes one position of the minimum length
+	offsetLength := length - 1
+	if offsetLength < 1 {
+		offsetLength = 1
+	}
+	return &hashidsGenerator{
+		seq:      seq,
+		offset:   sequenceOffset(offsetLength),
+		alphabet: consistentShuffle(base62Alphabet, salt),
+		salt:     salt,
+	}
+}
+
+// Generate obfuscates the next sequence value
+func (g *hashidsGenerator) Generate(attempt int) (string, error) {
+	if g.seq == nil {
+		return "", fmt.Errorf("no sequence configured for short codes")
+	}
+	value, err := g.seq.Next()
+	if err != nil {
+		return "", err
+	}
+
+	n := g.offset + g.mix(value)
+	lottery := g.alphabet[n%int64(len(g.alphabet))]
+	key := string(lottery) + g.salt + g.alphabet
+	return string(lottery) + encodeBase(n, consistentShuffle(g.alphabet, key[:len(g.alphabet)])), nil
+}
+
+// mixFactor (the 64-bit golden ratio) is odd and not a multiple of 31 or 61, so it is coprime with every
+// keyspace size 61*62^k and multiplying by it modulo the size permutes the keyspace
+const mixFactor = 0x9E3779B97F4A7C15
+
+// mix spreads consecutive values over the codes of the minimum length: values within that keyspace are permuted
+// by multiplying with mixFactor, larger ones are passed through above it, so the result stays unique
+func (g *hashidsGenerator) mix(value int64) int64 {
+	size := g.offset*int64(len(base62Alphabet)) - g.offset // Values whose code has the minimum length
+	if value < 0 || value >= size {
+		return value
+	}
+	hi, lo := bits.Mul64(uint64(value), mixFactor)
+	return int64(bits.Rem64(hi, lo, uint64(size)))
+}
+
+// consistentShuffle permutes alphabet deterministically for a salt (the shuffle of Hashids)
+func consistentShuffle(alphabet, salt string) string {
+	if salt == "" {
+		return alphabet
+	}
+	result := []byte(alphabet)
+	for i, v, p := len(result)-1, 0, 0; i > 0; i-- {
+		v %= len(salt)
+		p += int(salt[v])
+		j := (int(salt[v]) + v + p) % i
+		result[i], result[j] = result[j], result[i]
+		v++
+	}
+	return string(result)
+}
@@ -0,0 +1,63 @@
+package shortcode
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// adjectives and nouns are short, inoffensive words that are easy to read out and type
+var adjectives = []string{
+	"able", "amber", "azure", "bold", "brave", "brief", "bright", "brisk", "calm", "candid",
+	"cheery", "clever", "cosmic", "cozy", "crisp", "curly", "daring", "dapper", "eager", "early",
+	"easy", "epic", "fair", "fancy", "fast", "fluffy", "fond", "frank", "fresh", "gentle",
+	"giant", "glad", "golden", "grand", "green", "happy", "hardy", "honest", "humble", "jolly",
+	"keen", "kind", "lively", "lucky", "lunar", "magic", "mellow", "merry", "mighty", "misty",
+	"modest", "noble", "polite", "proud", "quick", "quiet", "rapid", "rosy", "royal", "rustic",
+	"shiny", "silent", "silver", "simple", "sleek", "smart", "snappy", "snowy", "solar", "spicy",
+	"steady", "stormy", "sunny", "super", "sweet", "swift", "tidy", "tiny", "true", "upbeat",
+	"vivid", "warm", "wavy", "wild", "wise", "witty", "young", "zany", "zesty", "zippy",
+}
+
+var nouns = []string{
+	"acorn", "anchor", "apple", "arrow", "badger", "bamboo", "beacon", "bear", "berry", "bison",
+	"breeze", "brook", "cactus", "canyon", "castle", "cedar", "cloud", "comet", "coral", "crane",
+	"daisy", "delta", "dolphin", "dune", "eagle", "ember", "falcon", "fern", "finch", "forest",
+	"fox", "galaxy", "garden", "gecko", "glacier", "harbor", "hawk", "heron", "island", "jaguar",
+	"kayak", "koala", "lagoon", "lantern", "lemon", "lily", "lotus", "maple", "meadow", "meteor",
+	"moose", "nebula", "oasis", "ocean", "orbit", "otter", "owl", "panda", "pebble", "pepper",
+	"pine", "planet", "pony", "prairie", "puffin", "quartz", "rabbit", "raven", "reef", "river",
+	"robin", "rocket", "sail", "salmon", "sparrow", "spruce", "summit", "tiger", "topaz", "tulip",
+	"valley", "violet", "walrus", "willow", "wombat", "yak", "zebra", "zephyr", "harp", "igloo",
+}
+
+// wordsGenerator combines adjectives and a noun, e.g. "brave-otter". Combinations of two words run out
+// quickly, so another adjective is added each time the keyspace is found to be filling up.
+type wordsGenerator struct {
+	growth growth
+}
+
+func newWordsGenerator() *wordsGenerator {
+	g := &wordsGenerator{}
+	g.growth.max = 2 // At most four words, which stays within MaxLength
+	return g
+}
+
+// Generate returns a random word combination
+func (g *wordsGenerator) Generate(attempt int) (string, error) {
+	count := 2 + g.growth.observe(attempt)
+	words := make([]string, count)
+	for i := range words {
+		list := adjectives
+		if i == count-1 {
+			list = nouns
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(list))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random number: %w", err)
+		}
+		words[i] = list[n.Int64()]
+	}
+	return strings.Join(words, "-"), nil
+}
@@ -0,0 +1,88 @@
+package useragent
+
+import "strings"
+
+// Device classes reported by Parse
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+)
+
+// Operating systems reported by Parse
+const (
+	OSiOS      = "ios"
+	OSAndroid  = "android"
+	OSWindows  = "windows"
+	OSMacOS    = "macos"
+	OSLinux    = "linux"
+	OSChromeOS = "chromeos"
+	OSOther    = "other"
+)
+
+// Devices lists every device class Parse can return
+var Devices = []string{DeviceDesktop, DeviceMobile, DeviceTablet, DeviceBot}
+
+// OperatingSystems lists every operating system Parse can return
+var OperatingSystems = []string{OSiOS, OSAndroid, OSWindows, OSMacOS, OSLinux, OSChromeOS, OSOther}
+
+// Info is the coarse classification of a User-Agent header
+type Info struct {
+	Device string
+	OS     string
+}
+
+// IsMobile reports whether the visitor is on a phone or tablet
+func (i Info) IsMobile() bool {
+	return i.Device == DeviceMobile || i.Device == DeviceTablet
+}
+
+// Parse classifies a User-Agent header into a device class and operating system.
+// It only needs to be good enough for routing decisions, not full browser detection.
+func Parse(userAgent string) Info {
+	ua := strings.ToLower(userAgent)
+
+	return Info{
+		Device: parseDevice(ua),
+		OS:     parseOS(ua),
+	}
+}
+
+func parseDevice(ua string) string {
+	switch {
+	case ua == "":
+		return DeviceDesktop
+	case strings.Contains(ua, "bot") || strings.Contains(ua, "crawler") || strings.Contains(ua, "spider") ||
+		strings.Contains(ua, "slurp") || strings.Contains(ua, "facebookexternalhit"):
+		return DeviceBot
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		return DeviceTablet
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod") ||
+		strings.Contains(ua, "android"):
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
+
+func parseOS(ua string) string {
+	// Order matters: iOS user agents contain "like Mac OS X" and Android ones contain "Linux"
+	switch {
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad") || strings.Contains(ua, "ipod"):
+		return OSiOS
+	case strings.Contains(ua, "android"):
+		return OSAndroid
+	case strings.Contains(ua, "windows"):
+		return OSWindows
+	case strings.Contains(ua, "cros"):
+		return OSChromeOS
+	case strings.Contains(ua, "macintosh") || strings.Contains(ua, "mac os x"):
+		return OSMacOS
+	case strings.Contains(ua, "linux"):
+		return OSLinux
+	default:
+		return OSOther
+	}
+}
@@ -2,22 +2,34 @@ package main
 
 import (
 	"log"
+	"net"
+	"os"
 	"time"
+	_ "time/tzdata" // Embedded timezone data for routing rule schedules
 
 	"shortly-be/internal/cache"
+	"shortly-be/internal/codefilter"
 	"shortly-be/internal/config"
 	"shortly-be/internal/controllers"
 	"shortly-be/internal/database"
+	"shortly-be/internal/geoip"
 	"shortly-be/internal/jwt"
 	"shortly-be/internal/middleware"
 	"shortly-be/internal/repository"
 	"shortly-be/internal/service"
+	"shortly-be/internal/shortcode"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
 )
 
 func main() {
+	// "shortly-be import ..." imports a shortener export from the command line instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		runImportCommand(os.Args[2:])
+		return
+	}
+
 	// Load configuration
 	cfg := config.Load()
 
@@ -43,9 +55,57 @@ func main() {
 		log.Println("Connected to Redis cache")
 	}
 
+	// Initialize GeoIP resolver (optional - country routing rules never match without it)
+	var geoResolver geoip.Resolver
+	if cfg.GeoIPDBPath != "" {
+		geoResolver, err = geoip.NewMMDBResolver(cfg.GeoIPDBPath)
+		if err != nil {
+			log.Printf("Warning: Failed to load GeoIP database (%v). Country routing disabled.", err)
+			geoResolver = nil
+		} else {
+			defer geoResolver.Close()
+			log.Println("Loaded GeoIP database")
+		}
+	}
+
+	// Load app association files for universal links / app links (optional)
+	appleAppSiteAssociation, err := config.LoadJSONFile(cfg.AppleAppSiteAssocPath)
+	if err != nil {
+		log.Printf("Warning: %v. iOS universal links disabled.", err)
+	}
+	assetLinks, err := config.LoadJSONFile(cfg.AssetLinksPath)
+	if err != nil {
+		log.Printf("Warning: %v. Android app links disabled.", err)
+	}
+
 	// Initialize repositories
 	urlRepo := repository.NewURLRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	ruleRepo := repository.NewRoutingRuleRepository(db)
+	destRepo := repository.NewDestinationRepository(db)
+	revRepo := repository.NewRevisionRepository(db)
+	domainRepo := repository.NewDomainRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	folderRepo := repository.NewFolderRepository(db)
+	utmPresetRepo := repository.NewUTMPresetRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	reservedWordRepo := repository.NewReservedWordRepository(db)
+
+	// Initialize the short code filter (reserved words and offensive-language blocklist)
+	codeFilter, err := newCodeFilter(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load short code blocklist: %v", err)
+	}
+	reservedWordService := service.NewReservedWordService(reservedWordRepo, codeFilter)
+	if err := reservedWordService.Reload(); err != nil {
+		log.Fatalf("Failed to load reserved words: %v", err)
+	}
+
+	// Initialize short code generators (deployment default strategy, overridable per user)
+	codeGenerators, err := newCodeGenerators(cfg, urlRepo, codeFilter)
+	if err != nil {
+		log.Fatalf("Invalid short code configuration: %v", err)
+	}
 
 	// Initialize JWT service
 	jwtService := jwt.NewJWTService(
@@ -54,13 +114,81 @@ func main() {
 	)
 
 	// Initialize services
-	urlService := service.NewURLService(urlRepo, cacheClient)
+	urlService := service.NewURLService(urlRepo, userRepo, ruleRepo, destRepo, revRepo, domainRepo, tagRepo, folderRepo, utmPresetRepo, cfg.TrackingParams, codeGenerators, codeFilter, geoResolver, cacheClient)
+	ruleService := service.NewRoutingRuleService(urlRepo, ruleRepo, cacheClient)
 	authService := service.NewAuthService(userRepo, jwtService)
+	userService := service.NewUserService(userRepo)
+	domainService := service.NewDomainService(domainRepo, net.DefaultResolver, cacheClient)
+	tagService := service.NewTagService(tagRepo)
+	folderService := service.NewFolderService(folderRepo)
+	utmPresetService := service.NewUTMPresetService(utmPresetRepo)
+	jobService := service.NewJobService(jobRepo, urlService)
+	trashService := service.NewTrashService(urlRepo, time.Duration(cfg.TrashRetentionDays)*24*time.Hour, cacheClient)
+
+	// Permanently delete links that have been in the trash longer than the retention period
+	go trashService.RunPurger(time.Duration(cfg.TrashPurgeInterval) * time.Minute)
+
+	// Hash the destinations of links created before reuse_existing canonicalized them, and store the skeletons
+	// of Unicode codes created before lookalike codes were rejected
+	go func() {
+		hashed, err := urlService.BackfillDestinationHashes()
+		if err != nil {
+			log.Printf("ERROR: Failed to backfill destination hashes: %v", err)
+		} else if hashed > 0 {
+			log.Printf("Backfilled destination hashes of %d links", hashed)
+		}
+
+		skeletons, err := urlService.BackfillCodeSkeletons()
+		if err != nil {
+			log.Printf("ERROR: Failed to backfill short code skeletons: %v", err)
+		} else if skeletons > 0 {
+			log.Printf("Backfilled short code skeletons of %d links", skeletons)
+		}
+	}()
+
+	// Fail background jobs whose process stopped before they finished, e.g. jobs interrupted by a restart
+	go jobService.RunReaper(time.Minute)
+
+	// Keep a pool of pre-generated short codes topped up so new links don't have to check theirs
+	var codePool *shortcode.Pool
+	if cfg.ShortCodePoolSize > 0 {
+		codePool, err = shortcode.NewPool(repository.NewCodePoolRepository(db), codeGenerators.Default(), shortcode.PoolConfig{
+			Size:      cfg.ShortCodePoolSize,
+			LeaseSize: cfg.ShortCodePoolLease,
+		})
+		if err != nil {
+			log.Fatalf("Invalid short code pool configuration: %v", err)
+		}
+		codeGenerators.UsePool(codePool)
+		go codePool.Run(time.Duration(cfg.ShortCodePoolInterval) * time.Second)
+	}
+
+	// Pick up words reserved through other instances
+	go reservedWordService.RunReloader(time.Duration(cfg.ReservedWordsReload) * time.Second)
 
 	// Initialize controllers
-	shortenerController := controllers.NewShortenerController(urlService, cfg.BaseURL)
+	shortenerController := controllers.NewShortenerController(
+		urlService,
+		jwtService,
+		cfg.BaseURL,
+		time.Duration(cfg.LinkUnlockTTL)*time.Minute,
+	)
 	authController := controllers.NewAuthController(authService)
+	userController := controllers.NewUserController(userService)
+	ruleController := controllers.NewRoutingRuleController(ruleService)
+	bulkController := controllers.NewBulkController(urlService, jobService, cfg.BaseURL)
+	jobController := controllers.NewJobController(jobService)
+	importController := controllers.NewImportController(jobService, cfg.BaseURL)
+	exportController := controllers.NewExportController(urlService, cfg.BaseURL)
+	trashController := controllers.NewTrashController(trashService)
+	domainController := controllers.NewDomainController(domainService)
+	tagController := controllers.NewTagController(tagService)
+	folderController := controllers.NewFolderController(folderService)
+	utmPresetController := controllers.NewUTMPresetController(utmPresetService)
+	wellKnownController := controllers.NewWellKnownController(appleAppSiteAssociation, assetLinks)
 	qrcodeController := controllers.NewQRCodeController(cfg.FrontendURL)
+	metricsController := controllers.NewMetricsController(codePool)
+	reservedWordController := controllers.NewReservedWordController(reservedWordService)
 
 	// Initialize rate limiters
 	generalRateLimiter := middleware.NewRateLimiter(rate.Limit(cfg.RateLimitRPS), cfg.RateLimitBurst)
@@ -78,8 +206,16 @@ func main() {
 		})
 	})
 
+	// App association files for iOS universal links and Android app links
+	router.GET("/.well-known/apple-app-site-association", wellKnownController.AppleAppSiteAssociation)
+	router.GET("/.well-known/assetlinks.json", wellKnownController.AssetLinks)
+
 	// Redirect endpoint with rate limiting
 	router.GET("/:shortCode", redirectRateLimiter.LimitMiddleware(), shortenerController.RedirectToURL)
+	router.GET("/:shortCode/*rest", redirectRateLimiter.LimitMiddleware(), shortenerController.RedirectToURL)
+	// Password submission for protected links (stricter auth rate limiting to slow down guessing)
+	router.POST("/:shortCode", authRateLimiter.LimitMiddleware(), shortenerController.UnlockURL)
+	router.POST("/:shortCode/*rest", authRateLimiter.LimitMiddleware(), shortenerController.UnlockURL)
 
 	// API v1 routes group with general rate limiting
 	api := router.Group("/api/v1")
@@ -99,24 +235,130 @@ func main() {
 		{
 			// URL shortening with stricter rate limiting
 			protected.POST("/shorten", shortenRateLimiter.LimitMiddleware(), shortenerController.CreateShortURL)
+			// Bulk creation from a JSON array or CSV upload (large uploads run as a background job)
+			protected.POST("/shorten/bulk", shortenRateLimiter.LimitMiddleware(), bulkController.CreateBulk)
+			protected.GET("/jobs/:jobId", jobController.GetJob)
+			// Import of Bitly, TinyURL and YOURLS exports (always runs as a background job)
+			protected.POST("/import", shortenRateLimiter.LimitMiddleware(), importController.ImportLinks)
 			
 			// Other URL routes (use general rate limiting from group)
 			protected.GET("/urls", shortenerController.GetUserURLs)
+			protected.GET("/analytics", shortenerController.GetUserClickAnalytics)
+			protected.GET("/analytics/campaigns", shortenerController.GetCampaignAnalytics)
+			protected.POST("/urls/batch", shortenerController.BatchUpdateURLs)
+			protected.GET("/urls/export", exportController.ExportURLs)
 			protected.GET("/url/:shortCode", shortenerController.GetURLStats)
 			protected.GET("/url/:shortCode/analytics", shortenerController.GetClickAnalytics)
-			protected.PATCH("/url/:shortCode", shortenerController.UpdateURLExpiresAt)
+			protected.GET("/url/:shortCode/analytics/variants", shortenerController.GetVariantAnalytics)
+			protected.GET("/url/:shortCode/analytics/export", exportController.ExportClicks)
+			protected.PATCH("/url/:shortCode", shortenerController.UpdateURL)
+			protected.GET("/url/:shortCode/revisions", shortenerController.GetURLRevisions)
+			protected.POST("/url/:shortCode/revisions/:revisionId/rollback", shortenerController.RollbackURL)
 			protected.DELETE("/url/:shortCode", shortenerController.DeleteURL)
+			protected.POST("/url/:shortCode/archive", shortenerController.ArchiveURL)
+			protected.DELETE("/url/:shortCode/archive", shortenerController.UnarchiveURL)
+
+			// Trash (deleted links can be restored until they are purged)
+			protected.GET("/trash", trashController.ListTrash)
+			protected.POST("/trash/:shortCode/restore", trashController.RestoreURL)
+			protected.DELETE("/trash/:shortCode", trashController.PurgeURL)
+
+			// Conditional routing rules (evaluated in order before the default destination)
+			protected.GET("/url/:shortCode/rules", ruleController.ListRules)
+			protected.POST("/url/:shortCode/rules", ruleController.CreateRule)
+			protected.PUT("/url/:shortCode/rules", ruleController.ReorderRules)
+			protected.PUT("/url/:shortCode/rules/:ruleId", ruleController.UpdateRule)
+			protected.DELETE("/url/:shortCode/rules/:ruleId", ruleController.DeleteRule)
+
+			// Tags and folders for organizing links
+			protected.GET("/tags", tagController.ListTags)
+			protected.POST("/tags", tagController.CreateTag)
+			protected.PATCH("/tags/:tagId", tagController.UpdateTag)
+			protected.DELETE("/tags/:tagId", tagController.DeleteTag)
+			protected.GET("/folders", folderController.ListFolders)
+			protected.POST("/folders", folderController.CreateFolder)
+			protected.PATCH("/folders/:folderId", folderController.UpdateFolder)
+			protected.DELETE("/folders/:folderId", folderController.DeleteFolder)
+
+			// Saved UTM parameter sets for the campaign builder of POST /shorten
+			protected.GET("/utm-presets", utmPresetController.ListPresets)
+			protected.POST("/utm-presets", utmPresetController.CreatePreset)
+			protected.PATCH("/utm-presets/:presetId", utmPresetController.UpdatePreset)
+			protected.DELETE("/utm-presets/:presetId", utmPresetController.DeletePreset)
+
+			// Custom domains (verified through a DNS TXT record before links can use them)
+			protected.GET("/domains", domainController.ListDomains)
+			protected.POST("/domains", domainController.AddDomain)
+			protected.POST("/domains/:hostname/verify", domainController.VerifyDomain)
+			protected.DELETE("/domains/:hostname", domainController.DeleteDomain)
+
+			// User preferences (defaults applied to new links)
+			protected.GET("/user/preferences", userController.GetPreferences)
+			protected.PATCH("/user/preferences", userController.UpdatePreferences)
+
+			// Admin routes (users.is_admin)
+			admin := protected.Group("/admin")
+			admin.Use(middleware.AdminMiddleware(userRepo))
+			{
+				// Words that can't be used as short codes
+				admin.GET("/reserved-words", reservedWordController.ListWords)
+				admin.POST("/reserved-words", reservedWordController.ReserveWord)
+				admin.DELETE("/reserved-words/:word", reservedWordController.ReleaseWord)
+			}
 		}
 		
 		// Public redirect endpoint with lenient rate limiting (same as direct redirect)
 		api.GET("/redirect/:shortCode", redirectRateLimiter.LimitMiddleware(), shortenerController.GetOriginalURLPublic)
+		api.POST("/redirect/:shortCode", authRateLimiter.LimitMiddleware(), shortenerController.UnlockURLPublic)
 		
 		// QR Code generation
 		api.GET("/qrcode/:shortCode", qrcodeController.GenerateQRCode)
 	}
 
+	// Reserve every top-level route path so no short code can shadow it
+	routePaths := make([]string, 0, len(router.Routes()))
+	for _, route := range router.Routes() {
+		routePaths = append(routePaths, route.Path)
+	}
+	codeFilter.ReserveRoutes(routePaths)
+
+	// Operational metrics in the Prometheus text format, on a separate listener that is kept off the public network
+	if cfg.MetricsAddr != "" {
+		metricsRouter := gin.New()
+		metricsRouter.GET("/metrics", metricsController.Metrics)
+		go func() {
+			log.Printf("Metrics listening on %s", cfg.MetricsAddr)
+			if err := metricsRouter.Run(cfg.MetricsAddr); err != nil {
+				log.Printf("ERROR: Metrics listener stopped: %v", err)
+			}
+		}()
+	}
+
 	// Start the server on port 8080
 	log.Println("Server starting on http://localhost:8080")
 	router.Run(":8080")
 }
 
+// newCodeGenerators creates the short code generators configured for the deployment; they skip codes codeFilter rejects
+func newCodeGenerators(cfg *config.Config, urlRepo repository.URLRepository, codeFilter *codefilter.Filter) (*shortcode.Generators, error) {
+	generators, err := shortcode.NewGenerators(shortcode.Config{
+		Strategy: cfg.ShortCodeStrategy,
+		Length:   cfg.ShortCodeLength,
+		Alphabet: cfg.ShortCodeAlphabet,
+		Salt:     cfg.ShortCodeSalt,
+	}, shortcode.SequenceFunc(urlRepo.NextCodeSequence))
+	if err != nil {
+		return nil, err
+	}
+	generators.UseFilter(codeFilter.Accept)
+	return generators, nil
+}
+
+// newCodeFilter creates the short code filter with the configured blocklist; reserved words are loaded separately
+func newCodeFilter(cfg *config.Config) (*codefilter.Filter, error) {
+	blocklist, err := codefilter.LoadBlocklist(cfg.BlocklistPath)
+	if err != nil {
+		return nil, err
+	}
+	return codefilter.New(blocklist, cfg.UnicodeShortCodes), nil
+}
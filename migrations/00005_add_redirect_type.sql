@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per-link HTTP redirect status code (301, 302, 307 or 308)
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS redirect_type SMALLINT NOT NULL DEFAULT 301
+CHECK (redirect_type IN (301, 302, 307, 308));
+
+-- Default redirect status code applied to new links of a user
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS default_redirect_type SMALLINT NOT NULL DEFAULT 301
+CHECK (default_redirect_type IN (301, 302, 307, 308));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS default_redirect_type;
+ALTER TABLE urls DROP COLUMN IF EXISTS redirect_type;
+-- +goose StatementEnd
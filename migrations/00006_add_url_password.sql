@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Optional bcrypt hash of the password required before redirecting
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS password_hash TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP COLUMN IF EXISTS password_hash;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Optional click limit after which the link stops redirecting (1 = burn after reading)
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS max_clicks INTEGER CHECK (max_clicks > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP COLUMN IF EXISTS max_clicks;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Optional activation time; the link does not redirect before it (stored in UTC like expires_at)
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_starts_at ON urls(starts_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_starts_at;
+ALTER TABLE urls DROP COLUMN IF EXISTS starts_at;
+-- +goose StatementEnd
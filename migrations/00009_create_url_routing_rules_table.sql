@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ordered conditional destinations evaluated before falling back to urls.original_url
+CREATE TABLE IF NOT EXISTS url_routing_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url_id UUID NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    destination TEXT NOT NULL,
+    conditions JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_url_routing_rules_url_id ON url_routing_rules(url_id, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_url_routing_rules_url_id;
+DROP TABLE IF EXISTS url_routing_rules;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Weighted destinations for A/B rotation; when present they replace original_url as the redirect target
+CREATE TABLE IF NOT EXISTS url_destinations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url_id UUID NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    weight INTEGER NOT NULL CHECK (weight > 0),
+    label VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_url_destinations_url_id ON url_destinations(url_id, position);
+
+-- Keep a visitor on the same variant via a cookie
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS sticky_variants BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Variant served for each click (NULL for links without destinations)
+ALTER TABLE url_clicks ADD COLUMN IF NOT EXISTS variant_id UUID REFERENCES url_destinations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_url_clicks_variant_id ON url_clicks(variant_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_url_clicks_variant_id;
+ALTER TABLE url_clicks DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE urls DROP COLUMN IF EXISTS sticky_variants;
+DROP INDEX IF EXISTS idx_url_destinations_url_id;
+DROP TABLE IF EXISTS url_destinations;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Mobile app deep link configuration (app URLs and store fallbacks per platform)
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS deep_link JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP COLUMN IF EXISTS deep_link;
+-- +goose StatementEnd
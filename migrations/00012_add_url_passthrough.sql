@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per-link forwarding of the visitor's query string and trailing path onto the destination
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS forward_query BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS query_precedence VARCHAR(16) NOT NULL DEFAULT 'destination'
+    CHECK (query_precedence IN ('destination', 'incoming'));
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS forward_path BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP COLUMN IF EXISTS forward_path;
+ALTER TABLE urls DROP COLUMN IF EXISTS query_precedence;
+ALTER TABLE urls DROP COLUMN IF EXISTS forward_query;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Previous versions of a link, written before every update so changes can be reviewed and rolled back
+CREATE TABLE IF NOT EXISTS url_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url_id UUID NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    original_url TEXT NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    redirect_type SMALLINT NOT NULL,
+    password_hash TEXT,
+    max_clicks INTEGER,
+    sticky_variants BOOLEAN NOT NULL DEFAULT FALSE,
+    deep_link JSONB,
+    forward_query BOOLEAN NOT NULL DEFAULT FALSE,
+    query_precedence VARCHAR(16) NOT NULL DEFAULT 'destination',
+    forward_path BOOLEAN NOT NULL DEFAULT FALSE,
+    destinations JSONB, -- A/B destinations at the time, NULL when there were none
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_url_revisions_url_id ON url_revisions(url_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_url_revisions_url_id;
+DROP TABLE IF EXISTS url_revisions;
+-- +goose StatementEnd
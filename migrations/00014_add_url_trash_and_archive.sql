@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Soft delete: trashed links stop redirecting but keep their clicks until purged
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+-- Archived links keep redirecting but are hidden from the default link list
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_urls_deleted_at ON urls(deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_deleted_at;
+ALTER TABLE urls DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE urls DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Custom branded domains; links can only be created on a domain once its DNS TXT record is verified
+CREATE TABLE IF NOT EXISTS domains (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hostname VARCHAR(253) UNIQUE NOT NULL,
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_domains_user_id ON domains(user_id);
+
+-- Short codes are unique per domain; '' is the default domain (BASE_URL)
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS domain VARCHAR(253) NOT NULL DEFAULT '';
+ALTER TABLE urls DROP CONSTRAINT IF EXISTS urls_short_code_key;
+ALTER TABLE urls ADD CONSTRAINT urls_domain_short_code_key UNIQUE (domain, short_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM urls WHERE domain <> '';
+ALTER TABLE urls DROP CONSTRAINT IF EXISTS urls_domain_short_code_key;
+ALTER TABLE urls ADD CONSTRAINT urls_short_code_key UNIQUE (short_code);
+ALTER TABLE urls DROP COLUMN IF EXISTS domain;
+DROP INDEX IF EXISTS idx_domains_user_id;
+DROP TABLE IF EXISTS domains;
+-- +goose StatementEnd
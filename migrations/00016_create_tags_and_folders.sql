@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Folder hierarchy per user; deleting a folder deletes its subfolders and unfiles their links
+CREATE TABLE IF NOT EXISTS folders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES folders(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_folders_user_id ON folders(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_unique_name
+    ON folders(user_id, COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), LOWER(name));
+
+-- User-defined tags, attached to links many-to-many
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_unique_name ON tags(user_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS url_tags (
+    url_id UUID NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (url_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_url_tags_tag_id ON url_tags(tag_id);
+
+-- Descriptive fields
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS title VARCHAR(255);
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS notes TEXT;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS metadata JSONB;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_urls_folder_id ON urls(folder_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_folder_id;
+ALTER TABLE urls DROP COLUMN IF EXISTS folder_id;
+ALTER TABLE urls DROP COLUMN IF EXISTS metadata;
+ALTER TABLE urls DROP COLUMN IF EXISTS notes;
+ALTER TABLE urls DROP COLUMN IF EXISTS title;
+DROP INDEX IF EXISTS idx_url_tags_tag_id;
+DROP TABLE IF EXISTS url_tags;
+DROP INDEX IF EXISTS idx_tags_unique_name;
+DROP TABLE IF EXISTS tags;
+DROP INDEX IF EXISTS idx_folders_unique_name;
+DROP INDEX IF EXISTS idx_folders_user_id;
+DROP TABLE IF EXISTS folders;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Full-text search over the short code, destination and title of links
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('simple', COALESCE(short_code, '') || ' ' || COALESCE(original_url, '') || ' ' || COALESCE(title, ''))
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_urls_search_vector ON urls USING GIN (search_vector);
+
+-- Keyset pagination of a user's links for each sort order
+CREATE INDEX IF NOT EXISTS idx_urls_user_created ON urls(user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_urls_user_clicks ON urls(user_id, click_count, id);
+CREATE INDEX IF NOT EXISTS idx_urls_user_expires ON urls(user_id, COALESCE(expires_at, 'infinity'::timestamptz), id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_user_expires;
+DROP INDEX IF EXISTS idx_urls_user_clicks;
+DROP INDEX IF EXISTS idx_urls_user_created;
+DROP INDEX IF EXISTS idx_urls_search_vector;
+ALTER TABLE urls DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lets click exports of a single link read its clicks in time order without sorting
+CREATE INDEX IF NOT EXISTS idx_url_clicks_url_id_clicked_at ON url_clicks(url_id, clicked_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_url_clicks_url_id_clicked_at;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- SHA-256 of the normalized destination, written by the application on insert and update,
+-- so shortening a URL again can find the user's existing link through an index
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS destination_hash CHAR(64);
+
+-- Backfill with the normalization of the application: lowercase scheme and host, no lone "/" path
+UPDATE urls
+SET destination_hash = encode(sha256(convert_to(
+    lower(COALESCE(substring(original_url from '^[A-Za-z][A-Za-z0-9+.-]*://[^/?#]*'), ''))
+    || regexp_replace(substring(original_url from length(COALESCE(substring(original_url from '^[A-Za-z][A-Za-z0-9+.-]*://[^/?#]*'), '')) + 1), '^/([?#]|$)', '\1'),
+    'UTF8')), 'hex')
+WHERE destination_hash IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_urls_user_destination ON urls(user_id, domain, destination_hash) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_user_destination;
+ALTER TABLE urls DROP COLUMN IF EXISTS destination_hash;
+-- +goose StatementEnd
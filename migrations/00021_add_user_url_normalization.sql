@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Query parameters stripped from new links' destinations (NULL uses the deployment's TRACKING_PARAMS)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tracking_params TEXT[];
+-- Whether #fragments are removed from new links' destinations
+ALTER TABLE users ADD COLUMN IF NOT EXISTS strip_fragments BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS strip_fragments;
+ALTER TABLE users DROP COLUMN IF EXISTS tracking_params;
+-- +goose StatementEnd
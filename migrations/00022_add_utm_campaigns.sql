@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Saved UTM parameter sets applied when creating links
+CREATE TABLE IF NOT EXISTS utm_presets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    source VARCHAR(255) NOT NULL DEFAULT '',
+    medium VARCHAR(255) NOT NULL DEFAULT '',
+    campaign VARCHAR(255) NOT NULL DEFAULT '',
+    term VARCHAR(255) NOT NULL DEFAULT '',
+    content VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_utm_presets_unique_name ON utm_presets(user_id, LOWER(name));
+
+-- utm_campaign of the destination, written by the application so analytics can group links by campaign
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS utm_campaign VARCHAR(255);
+
+-- Backfill from existing destinations ("+" is decoded, other percent-escapes are kept)
+UPDATE urls
+SET utm_campaign = NULLIF(LEFT(replace(substring(original_url from '[?&]utm_campaign=([^&#]*)'), '+', ' '), 255), '')
+WHERE original_url LIKE '%utm_campaign=%';
+
+CREATE INDEX IF NOT EXISTS idx_urls_user_campaign ON urls(user_id, utm_campaign) WHERE utm_campaign IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_user_campaign;
+ALTER TABLE urls DROP COLUMN IF EXISTS utm_campaign;
+DROP TABLE IF EXISTS utm_presets;
+-- +goose StatementEnd
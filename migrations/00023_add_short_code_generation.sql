@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Room for word-combination codes; search_vector depends on short_code and is recreated around the change
+ALTER TABLE urls DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE urls ALTER COLUMN short_code TYPE VARCHAR(32);
+ALTER TABLE urls ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('simple', COALESCE(short_code, '') || ' ' || COALESCE(original_url, '') || ' ' || COALESCE(title, ''))
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_urls_search_vector ON urls USING GIN (search_vector);
+
+-- Values of the sequence and hashids short code strategies
+CREATE SEQUENCE IF NOT EXISTS short_code_seq;
+
+-- Strategy for the user's generated codes (NULL uses the deployment's SHORT_CODE_STRATEGY)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS code_strategy VARCHAR(20);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS code_strategy;
+DROP SEQUENCE IF EXISTS short_code_seq;
+-- Codes longer than 10 characters must be removed before going back
+ALTER TABLE urls DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE urls ALTER COLUMN short_code TYPE VARCHAR(10);
+ALTER TABLE urls ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('simple', COALESCE(short_code, '') || ' ' || COALESCE(original_url, '') || ' ' || COALESCE(title, ''))
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_urls_search_vector ON urls USING GIN (search_vector);
+-- +goose StatementEnd
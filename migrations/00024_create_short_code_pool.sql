@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Pre-generated short codes that no link uses yet; instances lease batches of them for new links
+CREATE TABLE IF NOT EXISTS short_code_pool (
+    short_code VARCHAR(32) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS short_code_pool;
+-- +goose StatementEnd
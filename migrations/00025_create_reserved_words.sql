@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Words that can't be used as short codes, managed by admins (top-level routes are reserved by the application)
+CREATE TABLE IF NOT EXISTS reserved_words (
+    word VARCHAR(32) PRIMARY KEY,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The words that used to be hardcoded
+INSERT INTO reserved_words (word) VALUES
+    ('admin'), ('api'), ('www'), ('mail'), ('ftp'), ('localhost'), ('health'), ('metrics'), ('auth'),
+    ('login'), ('register'), ('signin'), ('signup'), ('signout'), ('logout'), ('shorten'), ('urls'),
+    ('url'), ('stats'), ('analytics'), ('redirect')
+ON CONFLICT (word) DO NOTHING;
+
+-- Admins manage deployment-wide settings such as the reserved words
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
+DROP TABLE IF EXISTS reserved_words;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Room for long vanity codes (up to 50 characters, emoji sequences take several); search_vector depends on
+-- short_code and is recreated around the change
+ALTER TABLE urls DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE urls ALTER COLUMN short_code TYPE VARCHAR(64);
+ALTER TABLE urls ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('simple', COALESCE(short_code, '') || ' ' || COALESCE(original_url, '') || ' ' || COALESCE(title, ''))
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_urls_search_vector ON urls USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Codes longer than 32 characters must be removed before going back
+ALTER TABLE urls DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE urls ALTER COLUMN short_code TYPE VARCHAR(32);
+ALTER TABLE urls ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('simple', COALESCE(short_code, '') || ' ' || COALESCE(original_url, '') || ' ' || COALESCE(title, ''))
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_urls_search_vector ON urls USING GIN (search_vector);
+-- +goose StatementEnd
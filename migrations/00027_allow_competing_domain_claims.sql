@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Several users may claim a hostname until one of them verifies it through DNS; verifying removes the
+-- other claims. Each user claims a hostname at most once.
+ALTER TABLE domains DROP CONSTRAINT IF EXISTS domains_hostname_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domains_verified_hostname ON domains(hostname) WHERE verified_at IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domains_user_hostname ON domains(user_id, hostname);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Keep one claim per hostname: the verified one, otherwise the oldest
+DELETE FROM domains d
+WHERE d.verified_at IS NULL AND EXISTS (
+    SELECT 1 FROM domains o
+    WHERE o.hostname = d.hostname AND o.id <> d.id
+      AND (o.verified_at IS NOT NULL OR o.created_at < d.created_at OR (o.created_at = d.created_at AND o.id < d.id))
+);
+DROP INDEX IF EXISTS idx_domains_user_hostname;
+DROP INDEX IF EXISTS idx_domains_verified_hostname;
+ALTER TABLE domains ADD CONSTRAINT domains_hostname_key UNIQUE (hostname);
+-- +goose StatementEnd
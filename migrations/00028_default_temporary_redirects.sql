@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- New links and users default to 302 so browsers don't cache redirects and skip click tracking; 301 stays
+-- available as an explicit choice. Existing links and preferences keep their stored status.
+ALTER TABLE urls ALTER COLUMN redirect_type SET DEFAULT 302;
+ALTER TABLE users ALTER COLUMN default_redirect_type SET DEFAULT 302;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ALTER COLUMN default_redirect_type SET DEFAULT 301;
+ALTER TABLE urls ALTER COLUMN redirect_type SET DEFAULT 301;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Revisions also keep a link's title, notes, metadata, folder and tag names so rollbacks restore them
+ALTER TABLE url_revisions ADD COLUMN IF NOT EXISTS title VARCHAR(255);
+ALTER TABLE url_revisions ADD COLUMN IF NOT EXISTS notes TEXT;
+ALTER TABLE url_revisions ADD COLUMN IF NOT EXISTS metadata JSONB;
+ALTER TABLE url_revisions ADD COLUMN IF NOT EXISTS folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+ALTER TABLE url_revisions ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE url_revisions DROP COLUMN IF EXISTS tags;
+ALTER TABLE url_revisions DROP COLUMN IF EXISTS folder_id;
+ALTER TABLE url_revisions DROP COLUMN IF EXISTS metadata;
+ALTER TABLE url_revisions DROP COLUMN IF EXISTS notes;
+ALTER TABLE url_revisions DROP COLUMN IF EXISTS title;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Running jobs touch heartbeat_at periodically; jobs whose process died stop doing so and are marked failed
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_jobs_unfinished ON jobs(heartbeat_at) WHERE status IN ('pending', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_jobs_unfinished;
+ALTER TABLE jobs DROP COLUMN IF EXISTS heartbeat_at;
+-- +goose StatementEnd
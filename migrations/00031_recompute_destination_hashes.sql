@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The backfill of 00020 only approximates the application's canonicalization (ports, punycode, query order and
+-- tracking parameters aren't handled), so older links weren't found by reuse_existing. Links without a hash are
+-- rehashed by the server on startup with the canonical destination.
+UPDATE urls SET destination_hash = NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Hashes the server hasn't recomputed yet go back to the approximation of 00020
+UPDATE urls
+SET destination_hash = encode(sha256(convert_to(
+    lower(COALESCE(substring(original_url from '^[A-Za-z][A-Za-z0-9+.-]*://[^/?#]*'), ''))
+    || regexp_replace(substring(original_url from length(COALESCE(substring(original_url from '^[A-Za-z][A-Za-z0-9+.-]*://[^/?#]*'), '')) + 1), '^/([?#]|$)', '\1'),
+    'UTF8')), 'hex')
+WHERE destination_hash IS NULL;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Skeleton of Unicode short codes (how they read, see codefilter.Skeleton), so two codes that can't be told apart
+-- can't coexist on a domain. Plain ASCII codes have none; existing Unicode codes are filled in by the server.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS code_skeleton TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_urls_domain_code_skeleton ON urls(domain, code_skeleton);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_domain_code_skeleton;
+ALTER TABLE urls DROP COLUMN IF EXISTS code_skeleton;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Room for reserving any custom short code (up to 50 characters)
+ALTER TABLE reserved_words ALTER COLUMN word TYPE VARCHAR(64);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Words longer than 32 characters must be released before going back
+ALTER TABLE reserved_words ALTER COLUMN word TYPE VARCHAR(32);
+-- +goose StatementEnd